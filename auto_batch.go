@@ -0,0 +1,152 @@
+package ethrpc
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AutoBatch coalesces single calls issued concurrently into batches
+type AutoBatch struct {
+	// Window is how long the first call waits for others to join the batch, 2ms by default
+	Window time.Duration
+	// MaxSize sends the batch as soon as it holds MaxSize calls, 100 by default
+	MaxSize int
+}
+
+type batchedCall struct {
+	ctx     context.Context
+	request EthRequest
+	ch      chan batchedResult
+}
+
+type batchedResult struct {
+	resp *EthResponse
+	err  error
+}
+
+// autoBatcher queues calls of client and flushes them with a single BatchCall
+type autoBatcher struct {
+	client  *rpcClient
+	window  time.Duration
+	maxSize int
+
+	mu    sync.Mutex
+	queue []*batchedCall
+	timer *time.Timer
+}
+
+func newAutoBatcher(client *rpcClient, config AutoBatch) *autoBatcher {
+	batcher := &autoBatcher{
+		client:  client,
+		window:  config.Window,
+		maxSize: config.MaxSize,
+	}
+	if batcher.window <= 0 {
+		batcher.window = 2 * time.Millisecond
+	}
+	if batcher.maxSize <= 0 {
+		batcher.maxSize = 100
+	}
+	return batcher
+}
+
+func (b *autoBatcher) call(ctx context.Context, request EthRequest) (*EthResponse, error) {
+	c := &batchedCall{
+		ctx:     ctx,
+		request: request,
+		ch:      make(chan batchedResult, 1),
+	}
+
+	b.mu.Lock()
+	b.queue = append(b.queue, c)
+	if len(b.queue) >= b.maxSize {
+		queue := b.take()
+		b.mu.Unlock()
+		go b.flush(queue)
+	} else {
+		if len(b.queue) == 1 {
+			b.timer = time.AfterFunc(b.window, b.flushPending)
+		}
+		b.mu.Unlock()
+	}
+
+	select {
+	case result := <-c.ch:
+		return result.resp, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// take empties queue, lock must be held
+func (b *autoBatcher) take() []*batchedCall {
+	queue := b.queue
+	b.queue = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	return queue
+}
+
+func (b *autoBatcher) flushPending() {
+	b.mu.Lock()
+	queue := b.take()
+	b.mu.Unlock()
+
+	if len(queue) > 0 {
+		b.flush(queue)
+	}
+}
+
+// flush sends queued calls, the batch is canceled once every caller gave up
+func (b *autoBatcher) flush(queue []*batchedCall) {
+	calls := queue[:0]
+	for _, c := range queue {
+		if c.ctx.Err() == nil {
+			calls = append(calls, c)
+		}
+	}
+	if len(calls) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for _, c := range calls {
+			select {
+			case <-c.ctx.Done():
+			case <-ctx.Done():
+				return
+			}
+		}
+		cancel()
+	}()
+
+	if len(calls) == 1 {
+		resp, err := b.client.roundTrip(ctx, calls[0].request)
+		calls[0].ch <- batchedResult{resp: resp, err: err}
+		return
+	}
+
+	requests := make([]EthRequest, len(calls))
+	index := make(map[int]int, len(calls))
+	for i, c := range calls {
+		requests[i] = c.request
+		index[c.request.ID] = i
+	}
+
+	resps, err := b.client.batchCall(ctx, requests)
+	if err == nil {
+		resps, err = matchResponses(requests, index, resps)
+	}
+	for i, c := range calls {
+		if err != nil {
+			c.ch <- batchedResult{err: err}
+		} else {
+			c.ch <- batchedResult{resp: &resps[i]}
+		}
+	}
+}
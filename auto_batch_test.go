@@ -0,0 +1,71 @@
+package ethrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoBatch(t *testing.T) {
+	var posts, batched int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&posts, 1)
+		body, _ := ioutil.ReadAll(r.Body)
+
+		receipt := func(req EthRequest) EthResponse {
+			hash := req.Params[0].(string)
+			if hash == "0x0" {
+				return EthResponse{ID: req.ID, Version: "2.0", Error: &EthError{Code: -32000, Message: "invalid hash"}}
+			}
+			return EthResponse{ID: req.ID, Version: "2.0", Result: json.RawMessage(`{"transactionHash":"` + hash + `"}`)}
+		}
+
+		if bytes.HasPrefix(body, []byte("[")) {
+			var reqs []EthRequest
+			json.Unmarshal(body, &reqs)
+			atomic.AddInt32(&batched, int32(len(reqs)))
+			resps := make([]EthResponse, len(reqs))
+			for i := range reqs {
+				resps[len(reqs)-1-i] = receipt(reqs[i])
+			}
+			json.NewEncoder(w).Encode(resps)
+			return
+		}
+
+		var req EthRequest
+		json.Unmarshal(body, &req)
+		json.NewEncoder(w).Encode(receipt(req))
+	}))
+	defer server.Close()
+
+	rpc := NewNodeAPI(server.URL, NodeAutoBatch(AutoBatch{Window: 20 * time.Millisecond, MaxSize: 10}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			hash := IntToHex(i)
+			receipt, err := rpc.EthGetTransactionReceipt(hash)
+			if i == 0 {
+				require.Equal(t, EthError{Code: -32000, Message: "invalid hash"}, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, hash, receipt.TransactionHash)
+		}(i)
+	}
+	wg.Wait()
+
+	require.True(t, atomic.LoadInt32(&posts) < 20)
+	require.True(t, atomic.LoadInt32(&batched) > 0)
+}
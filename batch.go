@@ -0,0 +1,466 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var errBatchNotExecuted = errors.New("batch not executed")
+
+// Batch collects requests sent together with a single BatchCall,
+// every request returns a handle holding its decoded result once batch is executed
+type Batch struct {
+	rpc   EthRPC
+	reqs  []EthRequest
+	items []batchItem
+}
+
+// NewBatch create new empty batch sent with rpc
+func NewBatch(rpc EthRPC) *Batch {
+	return &Batch{rpc: rpc}
+}
+
+type batchItem interface {
+	resolve(result json.RawMessage, err error)
+}
+
+func (b *Batch) add(item batchItem, method string, params ...interface{}) {
+	b.reqs = append(b.reqs, NewEthRequest(method, params...))
+	b.items = append(b.items, item)
+}
+
+// Len returns number of requests in batch
+func (b *Batch) Len() int {
+	return len(b.reqs)
+}
+
+// Execute sends batch, see ExecuteContext
+func (b *Batch) Execute() error {
+	return b.ExecuteContext(context.Background())
+}
+
+// ExecuteContext sends batch and resolves every handle with its result or error,
+// the returned error is the failure of whole batch which is set to every handle as well
+func (b *Batch) ExecuteContext(ctx context.Context) error {
+	if len(b.reqs) == 0 {
+		return nil
+	}
+
+	resps, err := b.rpc.BatchCallContext(ctx, b.reqs)
+	if err == nil && len(resps) != len(b.reqs) {
+		err = fmt.Errorf("batch of %d requests got %d responses", len(b.reqs), len(resps))
+	}
+
+	for i, item := range b.items {
+		switch {
+		case err != nil:
+			item.resolve(nil, err)
+		case resps[i].Error != nil:
+			item.resolve(nil, *resps[i].Error)
+		default:
+			item.resolve(resps[i].Result, nil)
+		}
+	}
+	return err
+}
+
+// batchResult - state shared by result handles
+type batchResult struct {
+	done bool
+	err  error
+}
+
+func (r *batchResult) check() error {
+	if !r.done {
+		return errBatchNotExecuted
+	}
+	return r.err
+}
+
+// RawResult - handle of raw method result
+type RawResult struct {
+	batchResult
+	value json.RawMessage
+}
+
+func (r *RawResult) resolve(result json.RawMessage, err error) {
+	r.done, r.value, r.err = true, result, err
+}
+
+// Result returns raw result of method call
+func (r *RawResult) Result() (json.RawMessage, error) {
+	return r.value, r.check()
+}
+
+// StringResult - handle of string result
+type StringResult struct {
+	batchResult
+	value string
+}
+
+func (r *StringResult) resolve(result json.RawMessage, err error) {
+	if err == nil {
+		err = unmarshalResult(result, &r.value)
+	}
+	r.done, r.err = true, err
+}
+
+// Result returns decoded result
+func (r *StringResult) Result() (string, error) {
+	return r.value, r.check()
+}
+
+// BoolResult - handle of bool result
+type BoolResult struct {
+	batchResult
+	value bool
+}
+
+func (r *BoolResult) resolve(result json.RawMessage, err error) {
+	if err == nil {
+		err = unmarshalResult(result, &r.value)
+	}
+	r.done, r.err = true, err
+}
+
+// Result returns decoded result
+func (r *BoolResult) Result() (bool, error) {
+	return r.value, r.check()
+}
+
+// IntResult - handle of hex encoded int result
+type IntResult struct {
+	batchResult
+	value int
+}
+
+func (r *IntResult) resolve(result json.RawMessage, err error) {
+	if err == nil {
+		r.value, err = decodeHexInt(result)
+	}
+	r.done, r.err = true, err
+}
+
+// Result returns decoded result
+func (r *IntResult) Result() (int, error) {
+	return r.value, r.check()
+}
+
+// BigIntResult - handle of hex encoded big.Int result
+type BigIntResult struct {
+	batchResult
+	value big.Int
+}
+
+func (r *BigIntResult) resolve(result json.RawMessage, err error) {
+	if err == nil {
+		r.value, err = decodeHexBig(result)
+	}
+	r.done, r.err = true, err
+}
+
+// Result returns decoded result
+func (r *BigIntResult) Result() (big.Int, error) {
+	return r.value, r.check()
+}
+
+// SyncingResult - handle of eth_syncing result
+type SyncingResult struct {
+	batchResult
+	value *Syncing
+}
+
+func (r *SyncingResult) resolve(result json.RawMessage, err error) {
+	if err == nil {
+		r.value, err = decodeSyncing(result)
+	}
+	r.done, r.err = true, err
+}
+
+// Result returns decoded result
+func (r *SyncingResult) Result() (*Syncing, error) {
+	return r.value, r.check()
+}
+
+// BlockResult - handle of block result
+type BlockResult struct {
+	batchResult
+	decode func(result json.RawMessage) (*Block, error)
+	value  *Block
+}
+
+func (r *BlockResult) resolve(result json.RawMessage, err error) {
+	if err == nil {
+		r.value, err = r.decode(result)
+	}
+	r.done, r.err = true, err
+}
+
+// Result returns decoded result
+func (r *BlockResult) Result() (*Block, error) {
+	return r.value, r.check()
+}
+
+// TransactionResult - handle of transaction result
+type TransactionResult struct {
+	batchResult
+	value *Transaction
+}
+
+func (r *TransactionResult) resolve(result json.RawMessage, err error) {
+	if err == nil {
+		r.value, err = decodeTransaction(result)
+	}
+	r.done, r.err = true, err
+}
+
+// Result returns decoded result
+func (r *TransactionResult) Result() (*Transaction, error) {
+	return r.value, r.check()
+}
+
+// TransactionReceiptResult - handle of transaction receipt result
+type TransactionReceiptResult struct {
+	batchResult
+	value *TransactionReceipt
+}
+
+func (r *TransactionReceiptResult) resolve(result json.RawMessage, err error) {
+	if err == nil {
+		r.value, err = decodeTransactionReceipt(result)
+	}
+	r.done, r.err = true, err
+}
+
+// Result returns decoded result
+func (r *TransactionReceiptResult) Result() (*TransactionReceipt, error) {
+	return r.value, r.check()
+}
+
+// LogsResult - handle of logs result
+type LogsResult struct {
+	batchResult
+	value []Log
+}
+
+func (r *LogsResult) resolve(result json.RawMessage, err error) {
+	if err == nil {
+		err = unmarshalResult(result, &r.value)
+	}
+	r.done, r.err = true, err
+}
+
+// Result returns decoded result
+func (r *LogsResult) Result() ([]Log, error) {
+	return r.value, r.check()
+}
+
+// Call adds raw method call.
+func (b *Batch) Call(method string, params ...interface{}) *RawResult {
+	r := new(RawResult)
+	b.add(r, method, params...)
+	return r
+}
+
+// Web3ClientVersion adds request of the current client version.
+func (b *Batch) Web3ClientVersion() *StringResult {
+	r := new(StringResult)
+	b.add(r, "web3_clientVersion")
+	return r
+}
+
+// NetVersion adds request of the current network protocol version.
+func (b *Batch) NetVersion() *StringResult {
+	r := new(StringResult)
+	b.add(r, "net_version")
+	return r
+}
+
+// NetListening adds request of client listening status.
+func (b *Batch) NetListening() *BoolResult {
+	r := new(BoolResult)
+	b.add(r, "net_listening")
+	return r
+}
+
+// NetPeerCount adds request of number of peers currently connected to the client.
+func (b *Batch) NetPeerCount() *IntResult {
+	r := new(IntResult)
+	b.add(r, "net_peerCount")
+	return r
+}
+
+// EthProtocolVersion adds request of the current ethereum protocol version.
+func (b *Batch) EthProtocolVersion() *StringResult {
+	r := new(StringResult)
+	b.add(r, "eth_protocolVersion")
+	return r
+}
+
+// EthSyncing adds request of the sync status.
+func (b *Batch) EthSyncing() *SyncingResult {
+	r := new(SyncingResult)
+	b.add(r, "eth_syncing")
+	return r
+}
+
+// EthGasPrice adds request of the current price per gas in wei.
+func (b *Batch) EthGasPrice() *BigIntResult {
+	r := new(BigIntResult)
+	b.add(r, "eth_gasPrice")
+	return r
+}
+
+// EthBlockNumber adds request of the number of most recent block.
+func (b *Batch) EthBlockNumber() *IntResult {
+	r := new(IntResult)
+	b.add(r, "eth_blockNumber")
+	return r
+}
+
+// EthGetBalance adds request of the balance of the account of given address in wei.
+func (b *Batch) EthGetBalance(address string, block BlockNumberOrTag) *BigIntResult {
+	r := new(BigIntResult)
+	b.add(r, "eth_getBalance", address, block)
+	return r
+}
+
+// EthGetStorageAt adds request of the value from a storage position at a given address.
+func (b *Batch) EthGetStorageAt(data string, position int, tag BlockNumberOrTag) *StringResult {
+	r := new(StringResult)
+	b.add(r, "eth_getStorageAt", data, IntToHex(position), tag)
+	return r
+}
+
+// EthGetTransactionCount adds request of the number of transactions sent from an address.
+func (b *Batch) EthGetTransactionCount(address string, block BlockNumberOrTag) *IntResult {
+	r := new(IntResult)
+	b.add(r, "eth_getTransactionCount", address, block)
+	return r
+}
+
+// EthCall adds request of message call executed without creating a transaction.
+func (b *Batch) EthCall(msg CallMsg, block BlockNumberOrTag) *StringResult {
+	r := new(StringResult)
+	b.add(r, "eth_call", msg, block)
+	return r
+}
+
+// EthEstimateGas adds request of the amount of gas the call takes.
+func (b *Batch) EthEstimateGas(msg CallMsg) *IntResult {
+	r := new(IntResult)
+	b.add(r, "eth_estimateGas", msg)
+	return r
+}
+
+// EthGetCode adds request of code at a given address.
+func (b *Batch) EthGetCode(address string, block BlockNumberOrTag) *StringResult {
+	r := new(StringResult)
+	b.add(r, "eth_getCode", address, block)
+	return r
+}
+
+// EthSendRawTransaction adds request sending signed transaction, the result is the transaction hash.
+func (b *Batch) EthSendRawTransaction(data string) *StringResult {
+	r := new(StringResult)
+	b.add(r, "eth_sendRawTransaction", data)
+	return r
+}
+
+// EthGetBlockTransactionCountByNumber adds request of the number of transactions in a block.
+func (b *Batch) EthGetBlockTransactionCountByNumber(number BlockNumberOrTag) *IntResult {
+	r := new(IntResult)
+	b.add(r, "eth_getBlockTransactionCountByNumber", number)
+	return r
+}
+
+// EthGetBlockTransactionCountByHash adds request of the number of transactions in a block by block hash.
+func (b *Batch) EthGetBlockTransactionCountByHash(hash string) *IntResult {
+	r := new(IntResult)
+	b.add(r, "eth_getBlockTransactionCountByHash", hash)
+	return r
+}
+
+// EthGetBlockByNumber adds request of block by block number.
+func (b *Batch) EthGetBlockByNumber(number BlockNumberOrTag, withTransactions bool) *BlockResult {
+	r := &BlockResult{decode: func(result json.RawMessage) (*Block, error) {
+		return decodeBlock(result, withTransactions)
+	}}
+	b.add(r, "eth_getBlockByNumber", number, withTransactions)
+	return r
+}
+
+// EthGetBlockByHash adds request of block by block hash.
+func (b *Batch) EthGetBlockByHash(hash string, withTransactions bool) *BlockResult {
+	r := &BlockResult{decode: func(result json.RawMessage) (*Block, error) {
+		return decodeBlock(result, withTransactions)
+	}}
+	b.add(r, "eth_getBlockByHash", hash, withTransactions)
+	return r
+}
+
+// EthGetTransactionByHash adds request of transaction by transaction hash.
+func (b *Batch) EthGetTransactionByHash(hash string) *TransactionResult {
+	r := new(TransactionResult)
+	b.add(r, "eth_getTransactionByHash", hash)
+	return r
+}
+
+// EthGetTransactionByBlockNumberAndIndex adds request of transaction by block number and transaction index position.
+func (b *Batch) EthGetTransactionByBlockNumberAndIndex(blockNumber BlockNumberOrTag, transactionIndex int) *TransactionResult {
+	r := new(TransactionResult)
+	b.add(r, "eth_getTransactionByBlockNumberAndIndex", blockNumber, IntToHex(transactionIndex))
+	return r
+}
+
+// EthGetTransactionByBlockHashAndIndex adds request of transaction by block hash and transaction index position.
+func (b *Batch) EthGetTransactionByBlockHashAndIndex(blockHash string, transactionIndex int) *TransactionResult {
+	r := new(TransactionResult)
+	b.add(r, "eth_getTransactionByBlockHashAndIndex", blockHash, IntToHex(transactionIndex))
+	return r
+}
+
+// EthGetTransactionReceipt adds request of the receipt of a transaction by transaction hash.
+func (b *Batch) EthGetTransactionReceipt(hash string) *TransactionReceiptResult {
+	r := new(TransactionReceiptResult)
+	b.add(r, "eth_getTransactionReceipt", hash)
+	return r
+}
+
+// EthGetLogs adds request of all logs matching a given filter object.
+func (b *Batch) EthGetLogs(params FilterParams) *LogsResult {
+	r := new(LogsResult)
+	b.add(r, "eth_getLogs", params)
+	return r
+}
+
+// EthGetUncleByBlockNumberAndIndex adds request of uncle by block number and uncle index position.
+func (b *Batch) EthGetUncleByBlockNumberAndIndex(number BlockNumberOrTag, pos int) *BlockResult {
+	r := &BlockResult{decode: decodeUncleBlock}
+	b.add(r, "eth_getUncleByBlockNumberAndIndex", number, IntToHex(pos))
+	return r
+}
+
+// EthGetUncleCountByBlockNumber adds request of the number of uncles in a block by block number.
+func (b *Batch) EthGetUncleCountByBlockNumber(number BlockNumberOrTag) *IntResult {
+	r := new(IntResult)
+	b.add(r, "eth_getUncleCountByBlockNumber", number)
+	return r
+}
+
+// EthGetUncleCountByBlockHash adds request of the number of uncles in a block by block hash.
+func (b *Batch) EthGetUncleCountByBlockHash(hash string) *IntResult {
+	r := new(IntResult)
+	b.add(r, "eth_getUncleCountByBlockHash", hash)
+	return r
+}
+
+// EthGetUncleByBlockHashAndIndex adds request of uncle by block hash and uncle index position.
+func (b *Batch) EthGetUncleByBlockHashAndIndex(hash string, pos int) *BlockResult {
+	r := &BlockResult{decode: decodeUncleBlock}
+	b.add(r, "eth_getUncleByBlockHashAndIndex", hash, IntToHex(pos))
+	return r
+}
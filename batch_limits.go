@@ -0,0 +1,106 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// BatchLimits bounds the batches sent in a single request,
+// larger batches are split into chunks sent concurrently and reassembled in order
+type BatchLimits struct {
+	// MaxSize is the max number of requests per chunk, 0 means unlimited
+	MaxSize int
+	// MaxBytes is the max encoded body size per chunk, 0 means unlimited
+	MaxBytes int
+	// Concurrency is the max number of chunks in flight, 4 by default
+	Concurrency int
+}
+
+func (limits BatchLimits) concurrency() int {
+	if limits.Concurrency <= 0 {
+		return 4
+	}
+	return limits.Concurrency
+}
+
+// split divides requests into chunks within limits,
+// a request larger than MaxBytes is sent alone
+func (limits BatchLimits) split(requests []EthRequest) ([][]EthRequest, error) {
+	if limits.MaxBytes <= 0 && (limits.MaxSize <= 0 || len(requests) <= limits.MaxSize) {
+		return [][]EthRequest{requests}, nil
+	}
+
+	var chunks [][]EthRequest
+	start, size := 0, 2
+	for i, request := range requests {
+		length := 0
+		if limits.MaxBytes > 0 {
+			body, err := json.Marshal(request)
+			if err != nil {
+				return nil, err
+			}
+			length = len(body) + 1
+		}
+
+		count := i - start
+		full := limits.MaxSize > 0 && count >= limits.MaxSize
+		oversize := limits.MaxBytes > 0 && count > 0 && size+length > limits.MaxBytes
+		if full || oversize {
+			chunks = append(chunks, requests[start:i])
+			start, size = i, 2
+		}
+		size += length
+	}
+	return append(chunks, requests[start:]), nil
+}
+
+// batchCall sends requests in chunks within limits, responses of chunks are concatenated in order
+func (x *rpcClient) batchCall(ctx context.Context, requests []EthRequest) ([]EthResponse, error) {
+	chunks, err := x.batchLimits.split(requests)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 1 {
+		return x.batchRoundTrip(ctx, requests)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]EthResponse, len(chunks))
+	errs := make([]error, len(chunks))
+	sem := make(chan struct{}, x.batchLimits.concurrency())
+	var wg sync.WaitGroup
+	for i := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			results[i], errs[i] = x.batchRoundTrip(ctx, chunks[i])
+			if errs[i] != nil {
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	resps := make([]EthResponse, 0, len(requests))
+	for i := range chunks {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		resps = append(resps, results[i]...)
+	}
+	return resps, nil
+}
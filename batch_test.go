@@ -0,0 +1,61 @@
+package ethrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []EthRequest
+		json.NewDecoder(r.Body).Decode(&reqs)
+
+		resps := make([]EthResponse, len(reqs))
+		for i, req := range reqs {
+			resps[i] = EthResponse{ID: req.ID, Version: "2.0"}
+			switch req.Method {
+			case "eth_blockNumber":
+				resps[i].Result = json.RawMessage(`"0x5dd091"`)
+			case "eth_getBlockByNumber":
+				resps[i].Result = json.RawMessage(`{"number":"0x5dd091","hash":"0xef7fa50f455e5c40f3435f2e1ede71fabf670f265ad623fb804ae2eb3c1d0db3","transactions":["0xfc7dcd42eb0b7898af2f52f7c5af3bd03cdf71ab8b3ed5b3d3a3ff0d91343cbe"]}`)
+			case "eth_getTransactionReceipt":
+				resps[i].Result = json.RawMessage(`null`)
+			default:
+				resps[i].Error = &EthError{Code: -32601, Message: "method not found"}
+			}
+		}
+		json.NewEncoder(w).Encode(resps)
+	}))
+	defer server.Close()
+
+	batch := NewBatch(NewNodeAPI(server.URL))
+	number := batch.EthBlockNumber()
+	block := batch.EthGetBlockByNumber(BlockNumber(6148241), false)
+	receipt := batch.EthGetTransactionReceipt("0xfc7dcd42eb0b7898af2f52f7c5af3bd03cdf71ab8b3ed5b3d3a3ff0d91343cbe")
+	peers := batch.NetPeerCount()
+	require.Equal(t, 4, batch.Len())
+
+	_, err := number.Result()
+	require.Equal(t, errBatchNotExecuted, err)
+
+	require.Nil(t, batch.Execute())
+
+	n, err := number.Result()
+	require.Nil(t, err)
+	require.Equal(t, 6148241, n)
+
+	b, err := block.Result()
+	require.Nil(t, err)
+	require.Equal(t, 6148241, b.Number)
+	require.Equal(t, "0xfc7dcd42eb0b7898af2f52f7c5af3bd03cdf71ab8b3ed5b3d3a3ff0d91343cbe", b.Transactions[0].Hash)
+
+	_, err = receipt.Result()
+	require.EqualError(t, err, "result null")
+
+	_, err = peers.Result()
+	require.Equal(t, EthError{Code: -32601, Message: "method not found"}, err)
+}
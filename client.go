@@ -0,0 +1,447 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+)
+
+// rpcClient implements EthRPC methods over a json-rpc Transport,
+// backends are configurations of transport around it
+type rpcClient struct {
+	backgroundRPC
+
+	transport   Transport
+	batchLimits BatchLimits
+	batcher     *autoBatcher
+	retry       RetryPolicy
+	limiter     *rateLimiter
+
+	// lastID is the id of last request sent by client
+	lastID int64
+}
+
+func (x *rpcClient) init() {
+	x.backgroundRPC = backgroundRPC{x}
+}
+
+func (x *rpcClient) Debug(debug bool) {
+	x.transport.Debug(debug)
+}
+
+// Close releases resources held by transport
+func (x *rpcClient) Close() error {
+	return x.transport.Close()
+}
+
+func (x *rpcClient) call(ctx context.Context, method string, target interface{}, params ...interface{}) error {
+	result, err := x.CallContext(ctx, method, params...)
+	if err != nil {
+		return err
+	}
+
+	return unmarshalResult(result, target)
+}
+
+// roundTrip sends request with retry policy, json-rpc errors are retried as well
+// and returned in the response of last attempt
+func (x *rpcClient) roundTrip(ctx context.Context, request EthRequest) (*EthResponse, error) {
+	var resp *EthResponse
+	err := x.retryPolicy(request).do(ctx, func(attempt int) (err error) {
+		if err := x.limit(ctx, 1); err != nil {
+			return err
+		}
+		resp, err = x.transport.Call(ctx, request)
+		if err == nil && resp.Error != nil {
+			return *resp.Error
+		}
+		return err
+	})
+	if _, ok := err.(EthError); err != nil && !ok {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// batchRoundTrip sends batch with retry policy
+func (x *rpcClient) batchRoundTrip(ctx context.Context, requests []EthRequest) ([]EthResponse, error) {
+	var resps []EthResponse
+	err := x.retryPolicy(requests...).do(ctx, func(attempt int) (err error) {
+		if err := x.limit(ctx, len(requests)); err != nil {
+			return err
+		}
+		resps, err = x.transport.BatchCall(ctx, requests)
+		return err
+	})
+	return resps, err
+}
+
+// retryPolicy returns retry policy of requests, raw transaction send which may have reached the node
+// is not retried after a timeout or 5xx, the node would reject the resent transaction as already known
+func (x *rpcClient) retryPolicy(requests ...EthRequest) RetryPolicy {
+	for _, request := range requests {
+		if request.Method == "eth_sendRawTransaction" {
+			return x.retry.restrict(isThrottled)
+		}
+	}
+	return x.retry
+}
+
+// limit takes n requests from rate limit budget
+func (x *rpcClient) limit(ctx context.Context, n int) error {
+	if x.limiter == nil {
+		return nil
+	}
+	return x.limiter.wait(ctx, n)
+}
+
+// RateLimitUsage returns usage of rate limit, zero when client is not rate limited
+func (x *rpcClient) RateLimitUsage() RateLimitUsage {
+	return x.limiter.stat()
+}
+
+func (x *rpcClient) nextID() int {
+	return int(atomic.AddInt64(&x.lastID, 1))
+}
+
+// BatchCallContext sends batch request, responses are returned in the order of reqs.
+// Requests are sent with ids unique to client, responses carry back the ids of reqs
+func (x *rpcClient) BatchCallContext(ctx context.Context, reqs []EthRequest) ([]EthResponse, error) {
+	requests := make([]EthRequest, len(reqs))
+	index := make(map[int]int, len(reqs))
+	for i, req := range reqs {
+		req.ID = x.nextID()
+		index[req.ID] = i
+		requests[i] = req
+	}
+
+	resps, err := x.batchCall(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	return matchResponses(reqs, index, resps)
+}
+
+// matchResponses orders resps as reqs using index of request ids
+func matchResponses(reqs []EthRequest, index map[int]int, resps []EthResponse) ([]EthResponse, error) {
+	ordered := make([]EthResponse, len(reqs))
+	filled := make([]bool, len(reqs))
+	for _, resp := range resps {
+		i, ok := index[resp.ID]
+		if !ok {
+			return nil, fmt.Errorf("unexpected response id %d", resp.ID)
+		}
+		if filled[i] {
+			return nil, fmt.Errorf("duplicated response id %d", resp.ID)
+		}
+		filled[i] = true
+		resp.ID = reqs[i].ID
+		ordered[i] = resp
+	}
+
+	for i := range filled {
+		if !filled[i] {
+			return nil, fmt.Errorf("missing response of request %d (%s)", i, reqs[i].Method)
+		}
+	}
+	return ordered, nil
+}
+
+// CallContext returns raw response of method call,
+// calls are coalesced into batches when auto batching is enabled
+func (x *rpcClient) CallContext(ctx context.Context, method string, params ...interface{}) (json.RawMessage, error) {
+	request := EthRequest{
+		ID:      x.nextID(),
+		Version: "2.0",
+		Method:  method,
+		Params:  params,
+	}
+
+	var resp *EthResponse
+	var err error
+	if x.batcher != nil {
+		resp, err = x.batcher.call(ctx, request)
+	} else {
+		resp, err = x.roundTrip(ctx, request)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Error != nil {
+		return nil, *resp.Error
+	}
+
+	return resp.Result, nil
+}
+
+// Web3ClientVersionContext returns the current client version.
+func (x *rpcClient) Web3ClientVersionContext(ctx context.Context) (string, error) {
+	var clientVersion string
+
+	err := x.call(ctx, "web3_clientVersion", &clientVersion)
+	return clientVersion, err
+}
+
+// NetVersionContext returns the current network protocol version.
+func (x *rpcClient) NetVersionContext(ctx context.Context) (string, error) {
+	var version string
+
+	err := x.call(ctx, "net_version", &version)
+	return version, err
+}
+
+// NetListeningContext returns true if client is actively listening for network connections.
+func (x *rpcClient) NetListeningContext(ctx context.Context) (bool, error) {
+	var listening bool
+
+	err := x.call(ctx, "net_listening", &listening)
+	return listening, err
+}
+
+// NetPeerCountContext returns number of peers currently connected to the client.
+func (x *rpcClient) NetPeerCountContext(ctx context.Context) (int, error) {
+	result, err := x.CallContext(ctx, "net_peerCount")
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeHexInt(result)
+}
+
+// EthProtocolVersionContext returns the current ethereum protocol version.
+func (x *rpcClient) EthProtocolVersionContext(ctx context.Context) (string, error) {
+	var protocolVersion string
+
+	err := x.call(ctx, "eth_protocolVersion", &protocolVersion)
+	return protocolVersion, err
+}
+
+// EthSyncingContext returns an object with data about the sync status or false.
+func (x *rpcClient) EthSyncingContext(ctx context.Context) (*Syncing, error) {
+	result, err := x.CallContext(ctx, "eth_syncing")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSyncing(result)
+}
+
+// EthGasPriceContext returns the current price per gas in wei.
+func (x *rpcClient) EthGasPriceContext(ctx context.Context) (big.Int, error) {
+	result, err := x.CallContext(ctx, "eth_gasPrice")
+	if err != nil {
+		return big.Int{}, err
+	}
+
+	return decodeHexBig(result)
+}
+
+// EthBlockNumberContext returns the number of most recent block.
+func (x *rpcClient) EthBlockNumberContext(ctx context.Context) (int, error) {
+	result, err := x.CallContext(ctx, "eth_blockNumber")
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeHexInt(result)
+}
+
+// EthGetBalanceContext returns the balance of the account of given address in wei.
+func (x *rpcClient) EthGetBalanceContext(ctx context.Context, address string, block BlockNumberOrTag) (big.Int, error) {
+	result, err := x.CallContext(ctx, "eth_getBalance", address, block)
+	if err != nil {
+		return big.Int{}, err
+	}
+
+	return decodeHexBig(result)
+}
+
+// EthGetStorageAtContext returns the value from a storage position at a given address.
+func (x *rpcClient) EthGetStorageAtContext(ctx context.Context, data string, position int, tag BlockNumberOrTag) (string, error) {
+	var result string
+
+	err := x.call(ctx, "eth_getStorageAt", &result, data, IntToHex(position), tag)
+	return result, err
+}
+
+// EthGetTransactionCountContext returns the number of transactions sent from an address.
+func (x *rpcClient) EthGetTransactionCountContext(ctx context.Context, address string, block BlockNumberOrTag) (int, error) {
+	result, err := x.CallContext(ctx, "eth_getTransactionCount", address, block)
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeHexInt(result)
+}
+
+// EthCallContext executes a new message call immediately without creating a transaction on the block chain.
+func (x *rpcClient) EthCallContext(ctx context.Context, msg CallMsg, block BlockNumberOrTag) (string, error) {
+	var result string
+
+	err := x.call(ctx, "eth_call", &result, msg, block)
+	return result, err
+}
+
+// EthEstimateGasContext makes a call and returns the amount of gas it takes, the call is not added to the block chain.
+func (x *rpcClient) EthEstimateGasContext(ctx context.Context, msg CallMsg) (int, error) {
+	result, err := x.CallContext(ctx, "eth_estimateGas", msg)
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeHexInt(result)
+}
+
+// EthGetCodeContext returns code at a given address.
+func (x *rpcClient) EthGetCodeContext(ctx context.Context, address string, block BlockNumberOrTag) (string, error) {
+	var code string
+
+	err := x.call(ctx, "eth_getCode", &code, address, block)
+	return code, err
+}
+
+// EthSendRawTransactionContext creates new message call transaction or a contract creation for signed transactions, it returns the transaction hash.
+func (x *rpcClient) EthSendRawTransactionContext(ctx context.Context, data string) (string, error) {
+	var hash string
+
+	err := x.call(ctx, "eth_sendRawTransaction", &hash, data)
+	return hash, err
+}
+
+// EthCallOverride executes message call against the state of block with overrides applied, see EthCallOverrideContext
+func (x *rpcClient) EthCallOverride(msg CallMsg, block BlockNumberOrTag, override StateOverride) (string, error) {
+	return x.EthCallOverrideContext(context.Background(), msg, block, override)
+}
+
+// EthCallOverrideContext executes message call against the state of block with overrides applied,
+// the overrides are not persisted. Supported by geth compatible nodes
+func (x *rpcClient) EthCallOverrideContext(ctx context.Context, msg CallMsg, block BlockNumberOrTag, override StateOverride) (string, error) {
+	var result string
+
+	err := x.call(ctx, "eth_call", &result, msg, block, override)
+	return result, err
+}
+
+// EthGetBlockTransactionCountByNumberContext returns the number of transactions in a block from a block matching the given block
+func (x *rpcClient) EthGetBlockTransactionCountByNumberContext(ctx context.Context, number BlockNumberOrTag) (int, error) {
+	result, err := x.CallContext(ctx, "eth_getBlockTransactionCountByNumber", number)
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeHexInt(result)
+}
+
+// EthGetBlockTransactionCountByHashContext returns the number of transactions in a block from a block matching the given block hash.
+func (x *rpcClient) EthGetBlockTransactionCountByHashContext(ctx context.Context, hash string) (int, error) {
+	result, err := x.CallContext(ctx, "eth_getBlockTransactionCountByHash", hash)
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeHexInt(result)
+}
+
+func (x *rpcClient) getBlock(ctx context.Context, method string, withTransactions bool, params ...interface{}) (*Block, error) {
+	result, err := x.CallContext(ctx, method, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeBlock(result, withTransactions)
+}
+
+// EthGetBlockByNumberContext returns information about a block by block number.
+func (x *rpcClient) EthGetBlockByNumberContext(ctx context.Context, number BlockNumberOrTag, withTransactions bool) (*Block, error) {
+	return x.getBlock(ctx, "eth_getBlockByNumber", withTransactions, number, withTransactions)
+}
+
+// EthGetBlockByHashContext returns information about a block by block hash.
+func (x *rpcClient) EthGetBlockByHashContext(ctx context.Context, hash string, withTransactions bool) (*Block, error) {
+	return x.getBlock(ctx, "eth_getBlockByHash", withTransactions, hash, withTransactions)
+}
+
+func (x *rpcClient) EthGetUncleByBlockNumberAndIndexContext(ctx context.Context, number BlockNumberOrTag, pos int) (*Block, error) {
+	result, err := x.CallContext(ctx, "eth_getUncleByBlockNumberAndIndex", number, IntToHex(pos))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeUncleBlock(result)
+}
+
+// EthGetUncleCountByBlockNumberContext returns the number of uncles in a block from a block matching the given block number.
+func (x *rpcClient) EthGetUncleCountByBlockNumberContext(ctx context.Context, number BlockNumberOrTag) (int, error) {
+	result, err := x.CallContext(ctx, "eth_getUncleCountByBlockNumber", number)
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeHexInt(result)
+}
+
+// EthGetUncleCountByBlockHashContext returns the number of uncles in a block from a block matching the given block hash.
+func (x *rpcClient) EthGetUncleCountByBlockHashContext(ctx context.Context, hash string) (int, error) {
+	result, err := x.CallContext(ctx, "eth_getUncleCountByBlockHash", hash)
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeHexInt(result)
+}
+
+// EthGetUncleByBlockHashAndIndexContext returns information about a uncle of a block by hash and uncle index position.
+func (x *rpcClient) EthGetUncleByBlockHashAndIndexContext(ctx context.Context, hash string, pos int) (*Block, error) {
+	result, err := x.CallContext(ctx, "eth_getUncleByBlockHashAndIndex", hash, IntToHex(pos))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeUncleBlock(result)
+}
+
+func (x *rpcClient) getTransaction(ctx context.Context, method string, params ...interface{}) (*Transaction, error) {
+	result, err := x.CallContext(ctx, method, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTransaction(result)
+}
+
+// EthGetTransactionByHashContext returns the information about a transaction requested by transaction hash.
+func (x *rpcClient) EthGetTransactionByHashContext(ctx context.Context, hash string) (*Transaction, error) {
+	return x.getTransaction(ctx, "eth_getTransactionByHash", hash)
+}
+
+// EthGetTransactionByBlockNumberAndIndexContext returns information about a transaction by block number and transaction index position.
+func (x *rpcClient) EthGetTransactionByBlockNumberAndIndexContext(ctx context.Context, blockNumber BlockNumberOrTag, transactionIndex int) (*Transaction, error) {
+	return x.getTransaction(ctx, "eth_getTransactionByBlockNumberAndIndex", blockNumber, IntToHex(transactionIndex))
+}
+
+// EthGetTransactionByBlockHashAndIndexContext returns information about a transaction by block hash and transaction index position.
+func (x *rpcClient) EthGetTransactionByBlockHashAndIndexContext(ctx context.Context, blockHash string, transactionIndex int) (*Transaction, error) {
+	return x.getTransaction(ctx, "eth_getTransactionByBlockHashAndIndex", blockHash, IntToHex(transactionIndex))
+}
+
+// EthGetTransactionReceiptContext returns the receipt of a transaction by transaction hash.
+// Note That the receipt is not available for pending transactions.
+func (x *rpcClient) EthGetTransactionReceiptContext(ctx context.Context, hash string) (*TransactionReceipt, error) {
+	result, err := x.CallContext(ctx, "eth_getTransactionReceipt", hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTransactionReceipt(result)
+}
+
+// EthGetLogsContext returns an array of all logs matching a given filter object.
+func (x *rpcClient) EthGetLogsContext(ctx context.Context, params FilterParams) ([]Log, error) {
+	var logs []Log
+	err := x.call(ctx, "eth_getLogs", &logs, params)
+	return logs, err
+}
@@ -0,0 +1,121 @@
+package ethrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newBatchServer answers batch with results equal to request ids, passed to edit before sending
+func newBatchServer(edit func(resps []EthResponse) []EthResponse) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []EthRequest
+		json.NewDecoder(r.Body).Decode(&reqs)
+
+		resps := make([]EthResponse, len(reqs))
+		for i, req := range reqs {
+			resps[i] = EthResponse{ID: req.ID, Version: "2.0", Result: json.RawMessage(`"` + req.Method + `"`)}
+		}
+		json.NewEncoder(w).Encode(edit(resps))
+	}))
+}
+
+func TestBatchCallOrder(t *testing.T) {
+	server := newBatchServer(func(resps []EthResponse) []EthResponse {
+		for i, j := 0, len(resps)-1; i < j; i, j = i+1, j-1 {
+			resps[i], resps[j] = resps[j], resps[i]
+		}
+		return resps
+	})
+	defer server.Close()
+
+	reqs := []EthRequest{
+		NewEthRequest("eth_blockNumber"),
+		NewEthRequest("eth_gasPrice"),
+		NewEthRequest("net_version"),
+	}
+	require.NotEqual(t, reqs[0].ID, reqs[1].ID)
+
+	resps, err := NewNodeAPI(server.URL).BatchCall(reqs)
+	require.Nil(t, err)
+	require.Len(t, resps, 3)
+	for i := range reqs {
+		require.Equal(t, reqs[i].ID, resps[i].ID)
+		require.Equal(t, `"`+reqs[i].Method+`"`, string(resps[i].Result))
+	}
+}
+
+func TestBatchCallMismatchedIDs(t *testing.T) {
+	missing := newBatchServer(func(resps []EthResponse) []EthResponse {
+		return resps[1:]
+	})
+	defer missing.Close()
+
+	_, err := NewNodeAPI(missing.URL).BatchCall([]EthRequest{NewEthRequest("eth_blockNumber"), NewEthRequest("eth_gasPrice")})
+	require.EqualError(t, err, "missing response of request 0 (eth_blockNumber)")
+
+	duplicated := newBatchServer(func(resps []EthResponse) []EthResponse {
+		return append(resps, resps[0])
+	})
+	defer duplicated.Close()
+
+	_, err = NewNodeAPI(duplicated.URL).BatchCall([]EthRequest{NewEthRequest("eth_blockNumber")})
+	require.EqualError(t, err, "duplicated response id 1")
+}
+
+func TestBatchLimitsSplit(t *testing.T) {
+	reqs := make([]EthRequest, 5)
+	for i := range reqs {
+		reqs[i] = EthRequest{ID: i + 1, Version: "2.0", Method: "eth_blockNumber", Params: []interface{}{}}
+	}
+
+	chunks, err := BatchLimits{MaxSize: 2}.split(reqs)
+	require.Nil(t, err)
+	require.Equal(t, [][]EthRequest{reqs[0:2], reqs[2:4], reqs[4:5]}, chunks)
+
+	// every request is encoded to 63 bytes
+	chunks, err = BatchLimits{MaxBytes: 130}.split(reqs)
+	require.Nil(t, err)
+	require.Equal(t, [][]EthRequest{reqs[0:2], reqs[2:4], reqs[4:5]}, chunks)
+
+	chunks, err = BatchLimits{MaxBytes: 10}.split(reqs[:2])
+	require.Nil(t, err)
+	require.Equal(t, [][]EthRequest{reqs[0:1], reqs[1:2]}, chunks)
+
+	chunks, err = BatchLimits{}.split(reqs)
+	require.Nil(t, err)
+	require.Equal(t, [][]EthRequest{reqs}, chunks)
+}
+
+func TestBatchCallChunks(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	server := newBatchServer(func(resps []EthResponse) []EthResponse {
+		mu.Lock()
+		sizes = append(sizes, len(resps))
+		mu.Unlock()
+		return resps
+	})
+	defer server.Close()
+
+	reqs := make([]EthRequest, 10)
+	for i := range reqs {
+		reqs[i] = NewEthRequest(IntToHex(i))
+	}
+
+	rpc := NewNodeAPI(server.URL, NodeBatchLimits(BatchLimits{MaxSize: 3, Concurrency: 2}))
+	resps, err := rpc.BatchCall(reqs)
+	require.Nil(t, err)
+	require.Len(t, resps, 10)
+	for i := range reqs {
+		require.Equal(t, reqs[i].ID, resps[i].ID)
+		require.Equal(t, `"`+IntToHex(i)+`"`, string(resps[i].Result))
+	}
+	sort.Ints(sizes)
+	require.Equal(t, []int{1, 3, 3, 3}, sizes)
+}
@@ -0,0 +1,164 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+)
+
+// backgroundRPC implements the blocking methods of EthRPC
+// by calling the context aware variant with context.Background()
+type backgroundRPC struct {
+	rpc EthRPCContext
+}
+
+// BatchCall sends batch request, see BatchCallContext
+func (x backgroundRPC) BatchCall(reqs []EthRequest) ([]EthResponse, error) {
+	return x.rpc.BatchCallContext(context.Background(), reqs)
+}
+
+// Call returns raw response of method call, see CallContext
+func (x backgroundRPC) Call(method string, params ...interface{}) (json.RawMessage, error) {
+	return x.rpc.CallContext(context.Background(), method, params...)
+}
+
+// Web3ClientVersion returns the current client version.
+func (x backgroundRPC) Web3ClientVersion() (string, error) {
+	return x.rpc.Web3ClientVersionContext(context.Background())
+}
+
+// NetVersion returns the current network protocol version.
+func (x backgroundRPC) NetVersion() (string, error) {
+	return x.rpc.NetVersionContext(context.Background())
+}
+
+// NetListening returns true if client is actively listening for network connections.
+func (x backgroundRPC) NetListening() (bool, error) {
+	return x.rpc.NetListeningContext(context.Background())
+}
+
+// NetPeerCount returns number of peers currently connected to the client.
+func (x backgroundRPC) NetPeerCount() (int, error) {
+	return x.rpc.NetPeerCountContext(context.Background())
+}
+
+// EthProtocolVersion returns the current ethereum protocol version.
+func (x backgroundRPC) EthProtocolVersion() (string, error) {
+	return x.rpc.EthProtocolVersionContext(context.Background())
+}
+
+// EthSyncing returns an object with data about the sync status or false.
+func (x backgroundRPC) EthSyncing() (*Syncing, error) {
+	return x.rpc.EthSyncingContext(context.Background())
+}
+
+// EthGasPrice returns the current price per gas in wei.
+func (x backgroundRPC) EthGasPrice() (big.Int, error) {
+	return x.rpc.EthGasPriceContext(context.Background())
+}
+
+// EthBlockNumber returns the number of most recent block.
+func (x backgroundRPC) EthBlockNumber() (int, error) {
+	return x.rpc.EthBlockNumberContext(context.Background())
+}
+
+// EthGetBalance returns the balance of the account of given address in wei.
+func (x backgroundRPC) EthGetBalance(address string, block BlockNumberOrTag) (big.Int, error) {
+	return x.rpc.EthGetBalanceContext(context.Background(), address, block)
+}
+
+// EthGetStorageAt returns the value from a storage position at a given address.
+func (x backgroundRPC) EthGetStorageAt(data string, position int, tag BlockNumberOrTag) (string, error) {
+	return x.rpc.EthGetStorageAtContext(context.Background(), data, position, tag)
+}
+
+// EthGetTransactionCount returns the number of transactions sent from an address.
+func (x backgroundRPC) EthGetTransactionCount(address string, block BlockNumberOrTag) (int, error) {
+	return x.rpc.EthGetTransactionCountContext(context.Background(), address, block)
+}
+
+// EthCall executes a new message call immediately without creating a transaction on the block chain.
+func (x backgroundRPC) EthCall(msg CallMsg, block BlockNumberOrTag) (string, error) {
+	return x.rpc.EthCallContext(context.Background(), msg, block)
+}
+
+// EthEstimateGas makes a call and returns the amount of gas it takes, the call is not added to the block chain.
+func (x backgroundRPC) EthEstimateGas(msg CallMsg) (int, error) {
+	return x.rpc.EthEstimateGasContext(context.Background(), msg)
+}
+
+// EthGetCode returns code at a given address.
+func (x backgroundRPC) EthGetCode(address string, block BlockNumberOrTag) (string, error) {
+	return x.rpc.EthGetCodeContext(context.Background(), address, block)
+}
+
+// EthSendRawTransaction creates new message call transaction or a contract creation for signed transactions, it returns the transaction hash.
+func (x backgroundRPC) EthSendRawTransaction(data string) (string, error) {
+	return x.rpc.EthSendRawTransactionContext(context.Background(), data)
+}
+
+// EthGetBlockTransactionCountByNumber returns the number of transactions in a block from a block matching the given block
+func (x backgroundRPC) EthGetBlockTransactionCountByNumber(number BlockNumberOrTag) (int, error) {
+	return x.rpc.EthGetBlockTransactionCountByNumberContext(context.Background(), number)
+}
+
+// EthGetBlockTransactionCountByHash returns the number of transactions in a block from a block matching the given block hash.
+func (x backgroundRPC) EthGetBlockTransactionCountByHash(hash string) (int, error) {
+	return x.rpc.EthGetBlockTransactionCountByHashContext(context.Background(), hash)
+}
+
+// EthGetBlockByNumber returns information about a block by block number.
+func (x backgroundRPC) EthGetBlockByNumber(number BlockNumberOrTag, withTransactions bool) (*Block, error) {
+	return x.rpc.EthGetBlockByNumberContext(context.Background(), number, withTransactions)
+}
+
+// EthGetBlockByHash returns information about a block by block hash.
+func (x backgroundRPC) EthGetBlockByHash(hash string, withTransactions bool) (*Block, error) {
+	return x.rpc.EthGetBlockByHashContext(context.Background(), hash, withTransactions)
+}
+
+// EthGetTransactionByHash returns the information about a transaction requested by transaction hash.
+func (x backgroundRPC) EthGetTransactionByHash(hash string) (*Transaction, error) {
+	return x.rpc.EthGetTransactionByHashContext(context.Background(), hash)
+}
+
+// EthGetTransactionByBlockNumberAndIndex returns information about a transaction by block number and transaction index position.
+func (x backgroundRPC) EthGetTransactionByBlockNumberAndIndex(blockNumber BlockNumberOrTag, transactionIndex int) (*Transaction, error) {
+	return x.rpc.EthGetTransactionByBlockNumberAndIndexContext(context.Background(), blockNumber, transactionIndex)
+}
+
+// EthGetTransactionByBlockHashAndIndex returns information about a transaction by block hash and transaction index position.
+func (x backgroundRPC) EthGetTransactionByBlockHashAndIndex(blockHash string, transactionIndex int) (*Transaction, error) {
+	return x.rpc.EthGetTransactionByBlockHashAndIndexContext(context.Background(), blockHash, transactionIndex)
+}
+
+// EthGetTransactionReceipt returns the receipt of a transaction by transaction hash.
+// Note That the receipt is not available for pending transactions.
+func (x backgroundRPC) EthGetTransactionReceipt(hash string) (*TransactionReceipt, error) {
+	return x.rpc.EthGetTransactionReceiptContext(context.Background(), hash)
+}
+
+// EthGetLogs returns an array of all logs matching a given filter object.
+func (x backgroundRPC) EthGetLogs(params FilterParams) ([]Log, error) {
+	return x.rpc.EthGetLogsContext(context.Background(), params)
+}
+
+// EthGetUncleByBlockNumberAndIndex returns information about a uncle of a block by number and uncle index position.
+func (x backgroundRPC) EthGetUncleByBlockNumberAndIndex(number BlockNumberOrTag, pos int) (*Block, error) {
+	return x.rpc.EthGetUncleByBlockNumberAndIndexContext(context.Background(), number, pos)
+}
+
+// EthGetUncleCountByBlockNumber returns the number of uncles in a block from a block matching the given block number.
+func (x backgroundRPC) EthGetUncleCountByBlockNumber(number BlockNumberOrTag) (int, error) {
+	return x.rpc.EthGetUncleCountByBlockNumberContext(context.Background(), number)
+}
+
+// EthGetUncleCountByBlockHash returns the number of uncles in a block from a block matching the given block hash.
+func (x backgroundRPC) EthGetUncleCountByBlockHash(hash string) (int, error) {
+	return x.rpc.EthGetUncleCountByBlockHashContext(context.Background(), hash)
+}
+
+// EthGetUncleByBlockHashAndIndex returns information about a uncle of a block by hash and uncle index position.
+func (x backgroundRPC) EthGetUncleByBlockHashAndIndex(hash string, pos int) (*Block, error) {
+	return x.rpc.EthGetUncleByBlockHashAndIndexContext(context.Background(), hash, pos)
+}
@@ -0,0 +1,106 @@
+package ethrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+)
+
+// decoders of raw method results shared by clients and batches
+
+func unmarshalResult(result json.RawMessage, target interface{}) error {
+	if target == nil {
+		return nil
+	}
+
+	if bytes.Equal(result, []byte("null")) {
+		return errResultNull
+	}
+	return json.Unmarshal(result, target)
+}
+
+func decodeHexInt(result json.RawMessage) (int, error) {
+	var response string
+	if err := unmarshalResult(result, &response); err != nil {
+		return 0, err
+	}
+
+	return ParseInt(response)
+}
+
+func decodeHexBig(result json.RawMessage) (big.Int, error) {
+	var response string
+	if err := unmarshalResult(result, &response); err != nil {
+		return big.Int{}, err
+	}
+
+	return ParseBigInt(response)
+}
+
+func decodeSyncing(result json.RawMessage) (*Syncing, error) {
+	syncing := new(Syncing)
+	if bytes.Equal(result, []byte("false")) {
+		return syncing, nil
+	}
+	err := json.Unmarshal(result, syncing)
+	return syncing, err
+}
+
+func decodeBlock(result json.RawMessage, withTransactions bool) (*Block, error) {
+	var response ProxyBlock
+	if withTransactions {
+		response = new(ProxyBlockWithTransactions)
+	} else {
+		response = new(ProxyBlockWithoutTransactions)
+	}
+
+	if err := unmarshalResult(result, response); err != nil {
+		return nil, err
+	}
+	block := response.ToBlock()
+	if len(block.Hash) == 0 {
+		return nil, errBlockNotFound
+	}
+
+	return &block, nil
+}
+
+func decodeUncleBlock(result json.RawMessage) (*Block, error) {
+	uncleBlock := new(UncleBlock)
+	if err := unmarshalResult(result, uncleBlock); err != nil {
+		return nil, err
+	}
+
+	block := uncleBlock.ToBlock()
+	if len(block.Hash) == 0 {
+		return nil, errBlockNotFound
+	}
+
+	return &block, nil
+}
+
+func decodeTransaction(result json.RawMessage) (*Transaction, error) {
+	transaction := new(Transaction)
+	if err := unmarshalResult(result, transaction); err != nil {
+		return nil, err
+	}
+
+	if len(transaction.Hash) == 0 {
+		return nil, errTxNotFound
+	}
+
+	return transaction, nil
+}
+
+func decodeTransactionReceipt(result json.RawMessage) (*TransactionReceipt, error) {
+	transactionReceipt := new(TransactionReceipt)
+	if err := unmarshalResult(result, transactionReceipt); err != nil {
+		return nil, err
+	}
+
+	if len(transactionReceipt.TransactionHash) == 0 {
+		return nil, errReceiptNotFound
+	}
+
+	return transactionReceipt, nil
+}
@@ -0,0 +1,136 @@
+package ethrpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound - requested block, transaction, receipt or data does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrNotSupported - backend does not implement the method
+	ErrNotSupported = errors.New("not supported")
+	// ErrRateLimited - request is rejected by backend or client side rate limit
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrNonceTooLow - transaction nonce is already used by the sender
+	ErrNonceTooLow = errors.New("nonce too low")
+	// ErrReplacementUnderpriced - pending transaction of the same nonce is not replaced for too low gas price
+	ErrReplacementUnderpriced = errors.New("replacement transaction underpriced")
+	// ErrInsufficientFunds - sender balance does not cover gas * price + value
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrAlreadyKnown - transaction is already in the pool of the node
+	ErrAlreadyKnown = errors.New("already known")
+)
+
+// txRejections - messages of rejected transaction by node implementation (geth, parity, nethermind)
+var txRejections = []struct {
+	err      error
+	messages []string
+}{
+	{ErrNonceTooLow, []string{"nonce too low", "nonce is too low", "oldnonce"}},
+	{ErrReplacementUnderpriced, []string{"replacement transaction underpriced", "another transaction with same nonce", "replacementnotallowed"}},
+	{ErrInsufficientFunds, []string{"insufficient funds", "insufficientfunds"}},
+	{ErrAlreadyKnown, []string{"already known", "known transaction", "already imported", "alreadyknown"}},
+}
+
+// isTxRejection reports if message of json-rpc error tells transaction is rejected for target
+func isTxRejection(message string, target error) bool {
+	message = strings.ToLower(message)
+	for _, rejection := range txRejections {
+		if rejection.err != target {
+			continue
+		}
+		for _, m := range rejection.messages {
+			if strings.Contains(message, m) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// json-rpc 2.0 and EIP-1474 error codes
+const (
+	CodeParseError          = -32700
+	CodeInvalidRequest      = -32600
+	CodeMethodNotFound      = -32601
+	CodeInvalidParams       = -32602
+	CodeInternalError       = -32603
+	CodeInvalidInput        = -32000
+	CodeResourceNotFound    = -32001
+	CodeResourceUnavailable = -32002
+	CodeTransactionRejected = -32003
+	CodeMethodNotSupported  = -32004
+	CodeLimitExceeded       = -32005
+	CodeVersionNotSupported = -32006
+)
+
+// notFoundError - missing data, the message tells what is missing
+type notFoundError string
+
+func (err notFoundError) Error() string {
+	return string(err)
+}
+
+func (err notFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+var (
+	errResultNull      = notFoundError("result null")
+	errBlockNotFound   = notFoundError("block not found")
+	errTxNotFound      = notFoundError("tx not found")
+	errReceiptNotFound = notFoundError("receipt not found")
+)
+
+// HTTPError - unexpected http status of backend response
+type HTTPError struct {
+	StatusCode int
+	Body       []byte
+	// RetryAfter is the delay asked by Retry-After header
+	RetryAfter time.Duration
+}
+
+// maxErrorBody bounds the response body kept in HTTPError
+const maxErrorBody = 4096
+
+func newHTTPError(response *http.Response) *HTTPError {
+	body, _ := ioutil.ReadAll(io.LimitReader(response.Body, maxErrorBody))
+	return &HTTPError{
+		StatusCode: response.StatusCode,
+		Body:       body,
+		RetryAfter: parseRetryAfter(response.Header.Get("Retry-After")),
+	}
+}
+
+func (err *HTTPError) Error() string {
+	return fmt.Sprintf("http status code error %d", err.StatusCode)
+}
+
+// Is reports 429 Too Many Requests as ErrRateLimited
+func (err *HTTPError) Is(target error) bool {
+	return target == ErrRateLimited && err.StatusCode == http.StatusTooManyRequests
+}
+
+// parseRetryAfter parses Retry-After header given in seconds or as http date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
@@ -0,0 +1,96 @@
+package ethrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthErrorClassification(t *testing.T) {
+	require.True(t, errors.Is(EthError{Code: CodeMethodNotFound}, ErrNotSupported))
+	require.True(t, errors.Is(EthError{Code: CodeMethodNotSupported}, ErrNotSupported))
+	require.True(t, errors.Is(EthError{Code: CodeResourceNotFound}, ErrNotFound))
+	require.True(t, errors.Is(EthError{Code: CodeLimitExceeded}, ErrRateLimited))
+	require.False(t, errors.Is(EthError{Code: CodeInvalidParams}, ErrNotSupported))
+
+	require.True(t, errors.Is(&HTTPError{StatusCode: http.StatusTooManyRequests}, ErrRateLimited))
+	require.False(t, errors.Is(&HTTPError{StatusCode: http.StatusBadGateway}, ErrRateLimited))
+	require.True(t, errors.Is(&RateLimitError{Requests: 1}, ErrRateLimited))
+
+	require.True(t, errors.Is(errBlockNotFound, ErrNotFound))
+	require.Equal(t, "block not found", errBlockNotFound.Error())
+}
+
+func TestTxRejection(t *testing.T) {
+	for message, target := range map[string]error{
+		"nonce too low": ErrNonceTooLow,
+		"Transaction nonce is too low. Try incrementing the nonce.": ErrNonceTooLow,
+		"replacement transaction underpriced":                       ErrReplacementUnderpriced,
+		"insufficient funds for gas * price + value":                ErrInsufficientFunds,
+		"already known": ErrAlreadyKnown,
+		"known transaction: 0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1": ErrAlreadyKnown,
+	} {
+		err := EthError{Code: CodeInvalidInput, Message: message}
+		require.True(t, errors.Is(err, target), message)
+		require.False(t, errors.Is(err, ErrNotFound), message)
+	}
+	require.False(t, errors.Is(EthError{Code: CodeInvalidInput, Message: "execution reverted"}, ErrNonceTooLow))
+}
+
+func TestEthErrorData(t *testing.T) {
+	resp := new(EthResponse)
+	err := json.Unmarshal([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted","data":"0x08c379a0"}}`), resp)
+	require.Nil(t, err)
+	require.Equal(t, 3, resp.Error.Code)
+	require.Equal(t, `"0x08c379a0"`, string(resp.Error.Data))
+}
+
+func TestHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("daily request count exceeded"))
+	}))
+	defer server.Close()
+
+	_, err := NewNodeAPI(server.URL).EthBlockNumber()
+	require.True(t, errors.Is(err, ErrRateLimited))
+
+	var httpErr *HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusTooManyRequests, httpErr.StatusCode)
+	require.Equal(t, "daily request count exceeded", string(httpErr.Body))
+	require.Equal(t, "7s", httpErr.RetryAfter.String())
+}
+
+func TestNotFoundError(t *testing.T) {
+	var hits int32
+	server := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":null}`, &hits)
+	defer server.Close()
+
+	rpc := NewNodeAPI(server.URL)
+	_, err := rpc.EthGetTransactionReceipt("0x0")
+	require.True(t, errors.Is(err, ErrNotFound))
+	_, err = rpc.EthGetBlockByNumber(BlockNumber(1), false)
+	require.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestTransactionNotFoundError(t *testing.T) {
+	var hits, malformedHits int32
+	server := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":null}`, &hits)
+	defer server.Close()
+	malformed := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":{"hash":1}}`, &malformedHits)
+	defer malformed.Close()
+
+	_, err := NewNodeAPI(server.URL).EthGetTransactionByHash("0x0")
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	// malformed transaction is not reported as missing
+	_, err = NewNodeAPI(malformed.URL).EthGetTransactionByHash("0x0")
+	require.NotNil(t, err)
+	require.False(t, errors.Is(err, ErrNotFound))
+}
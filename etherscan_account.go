@@ -0,0 +1,515 @@
+package ethrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+)
+
+// etherscanResultWindow - etherscan serves at most page * offset = 10000 records of a query
+const etherscanResultWindow = 10000
+
+// AccountQuery - filter of account history, every page of history is fetched
+type AccountQuery struct {
+	// StartBlock is the first block of history
+	StartBlock int
+	// EndBlock is the last block of history, 0 means latest
+	EndBlock int
+	// Contract filters token transfers of the token contract
+	Contract string
+	// PageSize is the number of records per request, 1000 by default
+	PageSize int
+	// Desc sorts history from the latest block
+	Desc bool
+}
+
+// AccountTransaction - normal transaction of account history
+type AccountTransaction struct {
+	BlockNumber       int
+	Timestamp         int
+	Hash              string
+	Nonce             int
+	BlockHash         string
+	TransactionIndex  int
+	From              string
+	To                string
+	ContractAddress   string
+	Value             big.Int
+	Gas               int
+	GasPrice          big.Int
+	GasUsed           int
+	CumulativeGasUsed int
+	IsError           bool
+	// ReceiptStatus is "1" for success, "0" for failure and empty before byzantium
+	ReceiptStatus string
+	Input         string
+	MethodID      string
+	FunctionName  string
+	Confirmations int
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *AccountTransaction) UnmarshalJSON(data []byte) error {
+	proxy := new(proxyAccountTransaction)
+	if err := json.Unmarshal(data, proxy); err != nil {
+		return err
+	}
+
+	*t = AccountTransaction{
+		BlockNumber:       int(proxy.BlockNumber),
+		Timestamp:         int(proxy.Timestamp),
+		Hash:              proxy.Hash,
+		Nonce:             int(proxy.Nonce),
+		BlockHash:         proxy.BlockHash,
+		TransactionIndex:  int(proxy.TransactionIndex),
+		From:              proxy.From,
+		To:                proxy.To,
+		ContractAddress:   proxy.ContractAddress,
+		Value:             big.Int(proxy.Value),
+		Gas:               int(proxy.Gas),
+		GasPrice:          big.Int(proxy.GasPrice),
+		GasUsed:           int(proxy.GasUsed),
+		CumulativeGasUsed: int(proxy.CumulativeGasUsed),
+		IsError:           proxy.IsError == "1",
+		ReceiptStatus:     proxy.ReceiptStatus,
+		Input:             proxy.Input,
+		MethodID:          proxy.MethodID,
+		FunctionName:      proxy.FunctionName,
+		Confirmations:     int(proxy.Confirmations),
+	}
+
+	return nil
+}
+
+// InternalTransaction - internal transaction (message call) of account history
+type InternalTransaction struct {
+	BlockNumber     int
+	Timestamp       int
+	Hash            string
+	From            string
+	To              string
+	ContractAddress string
+	Value           big.Int
+	Input           string
+	Type            string
+	Gas             int
+	GasUsed         int
+	TraceID         string
+	IsError         bool
+	ErrCode         string
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *InternalTransaction) UnmarshalJSON(data []byte) error {
+	proxy := new(proxyInternalTransaction)
+	if err := json.Unmarshal(data, proxy); err != nil {
+		return err
+	}
+
+	*t = InternalTransaction{
+		BlockNumber:     int(proxy.BlockNumber),
+		Timestamp:       int(proxy.Timestamp),
+		Hash:            proxy.Hash,
+		From:            proxy.From,
+		To:              proxy.To,
+		ContractAddress: proxy.ContractAddress,
+		Value:           big.Int(proxy.Value),
+		Input:           proxy.Input,
+		Type:            proxy.Type,
+		Gas:             int(proxy.Gas),
+		GasUsed:         int(proxy.GasUsed),
+		TraceID:         proxy.TraceID,
+		IsError:         proxy.IsError == "1",
+		ErrCode:         proxy.ErrCode,
+	}
+
+	return nil
+}
+
+// TokenTransfer - ERC-20, ERC-721 or ERC-1155 transfer of account history,
+// Value is the amount of ERC-20 and ERC-1155 tokens, TokenID is set for ERC-721 and ERC-1155
+type TokenTransfer struct {
+	BlockNumber       int
+	Timestamp         int
+	Hash              string
+	Nonce             int
+	BlockHash         string
+	TransactionIndex  int
+	From              string
+	To                string
+	ContractAddress   string
+	Value             big.Int
+	TokenID           big.Int
+	TokenName         string
+	TokenSymbol       string
+	TokenDecimal      int
+	Gas               int
+	GasPrice          big.Int
+	GasUsed           int
+	CumulativeGasUsed int
+	Input             string
+	Confirmations     int
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *TokenTransfer) UnmarshalJSON(data []byte) error {
+	proxy := new(proxyTokenTransfer)
+	if err := json.Unmarshal(data, proxy); err != nil {
+		return err
+	}
+
+	value := proxy.Value
+	if proxy.TokenValue != nil {
+		value = *proxy.TokenValue
+	}
+	*t = TokenTransfer{
+		BlockNumber:       int(proxy.BlockNumber),
+		Timestamp:         int(proxy.Timestamp),
+		Hash:              proxy.Hash,
+		Nonce:             int(proxy.Nonce),
+		BlockHash:         proxy.BlockHash,
+		TransactionIndex:  int(proxy.TransactionIndex),
+		From:              proxy.From,
+		To:                proxy.To,
+		ContractAddress:   proxy.ContractAddress,
+		Value:             big.Int(value),
+		TokenID:           big.Int(proxy.TokenID),
+		TokenName:         proxy.TokenName,
+		TokenSymbol:       proxy.TokenSymbol,
+		TokenDecimal:      int(proxy.TokenDecimal),
+		Gas:               int(proxy.Gas),
+		GasPrice:          big.Int(proxy.GasPrice),
+		GasUsed:           int(proxy.GasUsed),
+		CumulativeGasUsed: int(proxy.CumulativeGasUsed),
+		Input:             proxy.Input,
+		Confirmations:     int(proxy.Confirmations),
+	}
+
+	return nil
+}
+
+// MinedBlock - block or uncle mined by address
+type MinedBlock struct {
+	BlockNumber int
+	Timestamp   int
+	BlockReward big.Int
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *MinedBlock) UnmarshalJSON(data []byte) error {
+	proxy := new(proxyMinedBlock)
+	if err := json.Unmarshal(data, proxy); err != nil {
+		return err
+	}
+
+	*b = MinedBlock{
+		BlockNumber: int(proxy.BlockNumber),
+		Timestamp:   int(proxy.Timestamp),
+		BlockReward: big.Int(proxy.BlockReward),
+	}
+
+	return nil
+}
+
+// decInt - decimal int encoded as string, empty string is zero
+type decInt int
+
+func (i *decInt) UnmarshalJSON(data []byte) error {
+	value := string(bytes.Trim(data, `"`))
+	if value == "" {
+		*i = 0
+		return nil
+	}
+
+	result, err := strconv.Atoi(value)
+	*i = decInt(result)
+
+	return err
+}
+
+// decBig - decimal big.Int encoded as string, empty string is zero
+type decBig big.Int
+
+func (i *decBig) UnmarshalJSON(data []byte) error {
+	value := string(bytes.Trim(data, `"`))
+	if value == "" {
+		*i = decBig{}
+		return nil
+	}
+
+	result, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return fmt.Errorf("invalid decimal %s", value)
+	}
+	*i = decBig(*result)
+
+	return nil
+}
+
+type proxyAccountTransaction struct {
+	BlockNumber       decInt `json:"blockNumber"`
+	Timestamp         decInt `json:"timeStamp"`
+	Hash              string `json:"hash"`
+	Nonce             decInt `json:"nonce"`
+	BlockHash         string `json:"blockHash"`
+	TransactionIndex  decInt `json:"transactionIndex"`
+	From              string `json:"from"`
+	To                string `json:"to"`
+	ContractAddress   string `json:"contractAddress"`
+	Value             decBig `json:"value"`
+	Gas               decInt `json:"gas"`
+	GasPrice          decBig `json:"gasPrice"`
+	GasUsed           decInt `json:"gasUsed"`
+	CumulativeGasUsed decInt `json:"cumulativeGasUsed"`
+	IsError           string `json:"isError"`
+	ReceiptStatus     string `json:"txreceipt_status"`
+	Input             string `json:"input"`
+	MethodID          string `json:"methodId"`
+	FunctionName      string `json:"functionName"`
+	Confirmations     decInt `json:"confirmations"`
+}
+
+type proxyInternalTransaction struct {
+	BlockNumber     decInt `json:"blockNumber"`
+	Timestamp       decInt `json:"timeStamp"`
+	Hash            string `json:"hash"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	ContractAddress string `json:"contractAddress"`
+	Value           decBig `json:"value"`
+	Input           string `json:"input"`
+	Type            string `json:"type"`
+	Gas             decInt `json:"gas"`
+	GasUsed         decInt `json:"gasUsed"`
+	TraceID         string `json:"traceId"`
+	IsError         string `json:"isError"`
+	ErrCode         string `json:"errCode"`
+}
+
+type proxyTokenTransfer struct {
+	BlockNumber       decInt  `json:"blockNumber"`
+	Timestamp         decInt  `json:"timeStamp"`
+	Hash              string  `json:"hash"`
+	Nonce             decInt  `json:"nonce"`
+	BlockHash         string  `json:"blockHash"`
+	TransactionIndex  decInt  `json:"transactionIndex"`
+	From              string  `json:"from"`
+	To                string  `json:"to"`
+	ContractAddress   string  `json:"contractAddress"`
+	Value             decBig  `json:"value"`
+	TokenValue        *decBig `json:"tokenValue"`
+	TokenID           decBig  `json:"tokenID"`
+	TokenName         string  `json:"tokenName"`
+	TokenSymbol       string  `json:"tokenSymbol"`
+	TokenDecimal      decInt  `json:"tokenDecimal"`
+	Gas               decInt  `json:"gas"`
+	GasPrice          decBig  `json:"gasPrice"`
+	GasUsed           decInt  `json:"gasUsed"`
+	CumulativeGasUsed decInt  `json:"cumulativeGasUsed"`
+	Input             string  `json:"input"`
+	Confirmations     decInt  `json:"confirmations"`
+}
+
+type proxyMinedBlock struct {
+	BlockNumber decInt `json:"blockNumber"`
+	Timestamp   decInt `json:"timeStamp"`
+	BlockReward decBig `json:"blockReward"`
+}
+
+// historyRecord - fields of history records used to page through block ranges
+type historyRecord struct {
+	BlockNumber decInt `json:"blockNumber"`
+}
+
+// accountHistory fetches every page of account action into target slice pointer.
+// Etherscan serves 10000 records of a query, longer history of ranged actions is continued
+// from the block of the last record, records of that block are fetched again.
+// History of other actions and blocks of more than 10000 records are cut at the window,
+// records fetched so far are returned with ErrHistoryTruncated
+func (x *EtherscanAPI) accountHistory(ctx context.Context, action string, query url.Values, filter AccountQuery, ranged bool, target interface{}) error {
+	size := filter.PageSize
+	if size <= 0 {
+		size = 1000
+	}
+	if size > etherscanResultWindow {
+		size = etherscanResultWindow
+	}
+	start, end := filter.StartBlock, filter.EndBlock
+
+	sort := "asc"
+	if filter.Desc {
+		sort = "desc"
+	}
+	query.Set("sort", sort)
+	query.Set("offset", strconv.Itoa(size))
+
+	var records []json.RawMessage
+	var truncated error
+	for page := 1; truncated == nil; page++ {
+		if ranged {
+			query.Set("startblock", strconv.Itoa(start))
+			if end > 0 {
+				query.Set("endblock", strconv.Itoa(end))
+			}
+		}
+		query.Set("page", strconv.Itoa(page))
+
+		result, err := x.query(ctx, "account", action, query)
+		if err != nil {
+			return err
+		}
+		var batch []json.RawMessage
+		if err := unmarshalResult(result, &batch); err != nil {
+			return err
+		}
+		records = append(records, batch...)
+		if len(batch) < size {
+			break
+		}
+		if (page+1)*size <= etherscanResultWindow {
+			continue
+		}
+		if !ranged {
+			truncated = fmt.Errorf("etherscan %s history exceeds %d records: %w", action, etherscanResultWindow, ErrHistoryTruncated)
+			continue
+		}
+
+		// continue from the block of the last record, dropping its records fetched so far
+		var last historyRecord
+		if err := json.Unmarshal(records[len(records)-1], &last); err != nil {
+			return err
+		}
+		boundary := start
+		if filter.Desc {
+			boundary = end
+		}
+		if int(last.BlockNumber) == boundary {
+			// window holds a single block, continuing from it would fetch the same window
+			truncated = fmt.Errorf("etherscan %s history of block %d exceeds %d records: %w", action, last.BlockNumber, etherscanResultWindow, ErrHistoryTruncated)
+			continue
+		}
+		for len(records) > 0 {
+			var record historyRecord
+			if err := json.Unmarshal(records[len(records)-1], &record); err != nil {
+				return err
+			}
+			if record.BlockNumber != last.BlockNumber {
+				break
+			}
+			records = records[:len(records)-1]
+		}
+		if filter.Desc {
+			end = int(last.BlockNumber)
+		} else {
+			start = int(last.BlockNumber)
+		}
+		page = 0
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return err
+	}
+	return truncated
+}
+
+// AccountTransactions returns normal transactions of address, see AccountTransactionsContext
+func (x *EtherscanAPI) AccountTransactions(address string, filter AccountQuery) ([]AccountTransaction, error) {
+	return x.AccountTransactionsContext(context.Background(), address, filter)
+}
+
+// AccountTransactionsContext returns normal transactions of address in block range.
+func (x *EtherscanAPI) AccountTransactionsContext(ctx context.Context, address string, filter AccountQuery) ([]AccountTransaction, error) {
+	var transactions []AccountTransaction
+	query := url.Values{"address": {address}}
+	err := x.accountHistory(ctx, "txlist", query, filter, true, &transactions)
+	return transactions, err
+}
+
+// InternalTransactions returns internal transactions of address, see InternalTransactionsContext
+func (x *EtherscanAPI) InternalTransactions(address string, filter AccountQuery) ([]InternalTransaction, error) {
+	return x.InternalTransactionsContext(context.Background(), address, filter)
+}
+
+// InternalTransactionsContext returns internal transactions of address in block range.
+func (x *EtherscanAPI) InternalTransactionsContext(ctx context.Context, address string, filter AccountQuery) ([]InternalTransaction, error) {
+	var transactions []InternalTransaction
+	query := url.Values{"address": {address}}
+	err := x.accountHistory(ctx, "txlistinternal", query, filter, true, &transactions)
+	return transactions, err
+}
+
+// tokenTransfers returns token transfers of address, or of filter contract when address is empty
+func (x *EtherscanAPI) tokenTransfers(ctx context.Context, action, address string, filter AccountQuery) ([]TokenTransfer, error) {
+	query := url.Values{}
+	if address != "" {
+		query.Set("address", address)
+	}
+	if filter.Contract != "" {
+		query.Set("contractaddress", filter.Contract)
+	}
+
+	var transfers []TokenTransfer
+	err := x.accountHistory(ctx, action, query, filter, true, &transfers)
+	return transfers, err
+}
+
+// TokenTransfers returns ERC-20 transfers of address, see TokenTransfersContext
+func (x *EtherscanAPI) TokenTransfers(address string, filter AccountQuery) ([]TokenTransfer, error) {
+	return x.TokenTransfersContext(context.Background(), address, filter)
+}
+
+// TokenTransfersContext returns ERC-20 transfers of address in block range,
+// transfers of every holder are returned for empty address and filter contract.
+func (x *EtherscanAPI) TokenTransfersContext(ctx context.Context, address string, filter AccountQuery) ([]TokenTransfer, error) {
+	return x.tokenTransfers(ctx, "tokentx", address, filter)
+}
+
+// NFTTransfers returns ERC-721 transfers of address, see NFTTransfersContext
+func (x *EtherscanAPI) NFTTransfers(address string, filter AccountQuery) ([]TokenTransfer, error) {
+	return x.NFTTransfersContext(context.Background(), address, filter)
+}
+
+// NFTTransfersContext returns ERC-721 transfers of address in block range,
+// transfers of every holder are returned for empty address and filter contract.
+func (x *EtherscanAPI) NFTTransfersContext(ctx context.Context, address string, filter AccountQuery) ([]TokenTransfer, error) {
+	return x.tokenTransfers(ctx, "tokennfttx", address, filter)
+}
+
+// MultiTokenTransfers returns ERC-1155 transfers of address, see MultiTokenTransfersContext
+func (x *EtherscanAPI) MultiTokenTransfers(address string, filter AccountQuery) ([]TokenTransfer, error) {
+	return x.MultiTokenTransfersContext(context.Background(), address, filter)
+}
+
+// MultiTokenTransfersContext returns ERC-1155 transfers of address in block range,
+// transfers of every holder are returned for empty address and filter contract.
+func (x *EtherscanAPI) MultiTokenTransfersContext(ctx context.Context, address string, filter AccountQuery) ([]TokenTransfer, error) {
+	return x.tokenTransfers(ctx, "token1155tx", address, filter)
+}
+
+// MinedBlocks returns blocks mined by address, see MinedBlocksContext
+func (x *EtherscanAPI) MinedBlocks(address string, uncles bool, filter AccountQuery) ([]MinedBlock, error) {
+	return x.MinedBlocksContext(context.Background(), address, uncles, filter)
+}
+
+// MinedBlocksContext returns blocks or uncles mined by address,
+// block range and sort of filter are not supported by etherscan.
+// History is not paged past the 10000 records etherscan serves, longer history returns the first
+// 10000 blocks with ErrHistoryTruncated
+func (x *EtherscanAPI) MinedBlocksContext(ctx context.Context, address string, uncles bool, filter AccountQuery) ([]MinedBlock, error) {
+	query := url.Values{"address": {address}, "blocktype": {"blocks"}}
+	if uncles {
+		query.Set("blocktype", "uncles")
+	}
+
+	var blocks []MinedBlock
+	err := x.accountHistory(ctx, "getminedblocks", query, filter, false, &blocks)
+	return blocks, err
+}
@@ -0,0 +1,152 @@
+package ethrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEtherscanAccountTransactions(t *testing.T) {
+	server := newEtherscanServer(map[string]string{
+		"account/txlist":         `{"status":"1","message":"OK","result":[{"blockNumber":"14923678","timeStamp":"1654646411","hash":"0xc52783ad354aecc04c670047754f062e3d6d04e8f5b24774472651f9c3882c60","nonce":"1","blockHash":"0x7e1638fd2c6bdd05ffd83c1cf06c63e2f67d0f802084bef076d06bdcf86d1bb0","transactionIndex":"61","from":"0x9aa99c23f67c81701c772b106b4f83f6e858dd2e","to":"","value":"0","gas":"6000000","gasPrice":"83924748773","isError":"0","txreceipt_status":"1","input":"0x60806040","contractAddress":"0xc5102fe9359fd9a28f877a67e36b0f050d81a3cc","cumulativeGasUsed":"4431878","gasUsed":"4401852","confirmations":"122485","methodId":"0x60806040","functionName":""}]}`,
+		"account/token1155tx":    `{"status":"1","message":"OK","result":[{"blockNumber":"13472395","timeStamp":"1634973285","hash":"0x643b15f3ffaad5d38e33e5872b4ebaa7a643eda8b50ffd5331f682934ee65d4d","nonce":"41","blockHash":"0xa5da536dfbe8125eb146114e2ee0d0bdef2b20483aacbf30fed6b60f092059e6","transactionIndex":"100","gas":"140000","gasPrice":"52898577246","gasUsed":"105030","cumulativeGasUsed":"11739203","input":"deprecated","contractAddress":"0x76be3b62873462d2142405439777e971754e8e77","from":"0x1e63326a84d2fa207bdfa856da9278a93deba418","to":"0x83f564d180b58ad9a02a449105568189ee7de8cb","tokenID":"10371","tokenValue":"1","tokenName":"parallel","tokenSymbol":"LL","confirmations":"1447769"}]}`,
+		"account/getminedblocks": `{"status":"0","message":"No records found","result":[]}`,
+	}, nil)
+	defer server.Close()
+
+	rpc := NewEtherscanAPI("key", EtherscanURL(server.URL))
+
+	transactions, err := rpc.AccountTransactions("0x9aa99c23f67c81701c772b106b4f83f6e858dd2e", AccountQuery{})
+	require.Nil(t, err)
+	require.Len(t, transactions, 1)
+	require.Equal(t, 14923678, transactions[0].BlockNumber)
+	require.Equal(t, "83924748773", transactions[0].GasPrice.String())
+	require.Equal(t, "0xc5102fe9359fd9a28f877a67e36b0f050d81a3cc", transactions[0].ContractAddress)
+	require.False(t, transactions[0].IsError)
+
+	transfers, err := rpc.MultiTokenTransfers("0x83f564d180b58ad9a02a449105568189ee7de8cb", AccountQuery{})
+	require.Nil(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, "10371", transfers[0].TokenID.String())
+	require.Equal(t, "1", transfers[0].Value.String())
+
+	blocks, err := rpc.MinedBlocks("0x9dd134d14d1e65f84b706d6f205cd5b1cd03a46b", false, AccountQuery{})
+	require.Nil(t, err)
+	require.Len(t, blocks, 0)
+}
+
+func TestEtherscanMinedBlocksWindow(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		pages = append(pages, query.Get("page"))
+		page, _ := strconv.Atoi(query.Get("page"))
+		offset, _ := strconv.Atoi(query.Get("offset"))
+
+		result := []map[string]string{}
+		for i := (page - 1) * offset; i < page*offset; i++ {
+			result = append(result, map[string]string{"blockNumber": strconv.Itoa(i), "timeStamp": "1654646411", "blockReward": "2000000000000000000"})
+		}
+		data, _ := json.Marshal(result)
+		w.Write([]byte(`{"status":"1","message":"OK","result":` + string(data) + `}`))
+	}))
+	defer server.Close()
+
+	rpc := NewEtherscanAPI("key", EtherscanURL(server.URL))
+
+	// mined blocks are not ranged, history is cut at the window of 10000 records
+	blocks, err := rpc.MinedBlocks("0x9dd134d14d1e65f84b706d6f205cd5b1cd03a46b", false, AccountQuery{PageSize: 5000})
+	require.True(t, errors.Is(err, ErrHistoryTruncated))
+	require.Len(t, blocks, etherscanResultWindow)
+	require.Equal(t, 9999, blocks[len(blocks)-1].BlockNumber)
+	require.Equal(t, []string{"1", "2"}, pages)
+}
+
+func TestEtherscanAccountPagination(t *testing.T) {
+	// two records per block
+	const total = 12001
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		requests = append(requests, query.Get("startblock")+"/"+query.Get("page"))
+		start, _ := strconv.Atoi(query.Get("startblock"))
+		page, _ := strconv.Atoi(query.Get("page"))
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		if page*offset > etherscanResultWindow {
+			w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Result window is too large"}`))
+			return
+		}
+
+		result := []map[string]string{}
+		skip := (page - 1) * offset
+		for i := start * 2; i < total && len(result) < offset; i++ {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			result = append(result, map[string]string{"blockNumber": strconv.Itoa(i / 2), "hash": fmt.Sprintf("0x%x", i)})
+		}
+		data, _ := json.Marshal(result)
+		w.Write([]byte(`{"status":"1","message":"OK","result":` + string(data) + `}`))
+	}))
+	defer server.Close()
+
+	rpc := NewEtherscanAPI("key", EtherscanURL(server.URL))
+
+	transactions, err := rpc.AccountTransactions("0x0", AccountQuery{PageSize: 4000})
+	require.Nil(t, err)
+	require.Len(t, transactions, total)
+	for i, tx := range transactions {
+		require.Equal(t, fmt.Sprintf("0x%x", i), tx.Hash)
+	}
+	// third page exceeds window of 10000 records, history continues from block 3999 of the last record
+	require.Equal(t, []string{"0/1", "0/2", "3999/1", "3999/2"}, requests)
+}
+
+func TestEtherscanAccountPaginationSingleBlock(t *testing.T) {
+	// two records per block up to block 3000, which holds the remaining 14000 records
+	const total = 20000
+	block := func(i int) int {
+		if i < 6000 {
+			return i / 2
+		}
+		return 3000
+	}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		requests = append(requests, query.Get("startblock")+"/"+query.Get("page"))
+		start, _ := strconv.Atoi(query.Get("startblock"))
+		page, _ := strconv.Atoi(query.Get("page"))
+		offset, _ := strconv.Atoi(query.Get("offset"))
+
+		first := 0
+		for first < total && block(first) < start {
+			first++
+		}
+		result := []map[string]string{}
+		for i := first + (page-1)*offset; i < total && len(result) < offset; i++ {
+			result = append(result, map[string]string{"blockNumber": strconv.Itoa(block(i)), "hash": fmt.Sprintf("0x%x", i)})
+		}
+		data, _ := json.Marshal(result)
+		w.Write([]byte(`{"status":"1","message":"OK","result":` + string(data) + `}`))
+	}))
+	defer server.Close()
+
+	rpc := NewEtherscanAPI("key", EtherscanURL(server.URL))
+
+	// second window is all block 3000, history stops there with the records fetched so far
+	transactions, err := rpc.AccountTransactions("0x0", AccountQuery{PageSize: 5000})
+	require.True(t, errors.Is(err, ErrHistoryTruncated))
+	require.Len(t, transactions, 16000)
+	for i, tx := range transactions {
+		require.Equal(t, fmt.Sprintf("0x%x", i), tx.Hash)
+	}
+	require.Equal(t, []string{"0/1", "0/2", "3000/1", "3000/2"}, requests)
+}
@@ -1,30 +1,95 @@
 package ethrpc
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/big"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 type EtherscanAPI struct {
-	tokens []string
-	url string
+	backgroundRPC
+
+	keys   *keyPool
+	url    string
 	client *http.Client
 	debug  bool
+	retry  RetryPolicy
+
+	limiter  *rateLimiter
+	cacheDir string
+
+	batchConcurrency int
+}
+
+// EtherscanURL set api url, e.g. https://api-sepolia.etherscan.io/api for testnet
+func EtherscanURL(url string) func(x *EtherscanAPI) {
+	return func(x *EtherscanAPI) {
+		x.url = url
+	}
+}
+
+// EtherscanHTTPClient set http client used to reach etherscan
+func EtherscanHTTPClient(client *http.Client) func(x *EtherscanAPI) {
+	return func(x *EtherscanAPI) {
+		x.client = client
+	}
+}
+
+// EtherscanBatchConcurrency set the max number of calls in flight of emulated batch, 4 by default
+func EtherscanBatchConcurrency(concurrency int) func(x *EtherscanAPI) {
+	return func(x *EtherscanAPI) {
+		if concurrency > 0 {
+			x.batchConcurrency = concurrency
+		}
+	}
+}
+
+// EtherscanKeyRate set requests per second of every api key, 5 by default as of free api keys,
+// keys are not limited for non positive rate
+func EtherscanKeyRate(perSecond float64) func(x *EtherscanAPI) {
+	return func(x *EtherscanAPI) {
+		x.keys.setRate(perSecond)
+	}
+}
+
+// EtherscanRetry set retry policy of failed calls, every attempt is sent with next api key
+func EtherscanRetry(policy RetryPolicy) func(x *EtherscanAPI) {
+	return func(x *EtherscanAPI) {
+		if policy.Retryable == nil {
+			policy.Retryable = etherscanRetryable
+		}
+		x.retry = policy
+	}
+}
+
+// EtherscanRateLimit set client side rate limit of requests, free api keys allow 5 requests per second
+func EtherscanRateLimit(limit RateLimit) func(x *EtherscanAPI) {
+	return func(x *EtherscanAPI) {
+		x.limiter = newRateLimiter(limit)
+	}
 }
 
 // New create new rpc client with given url
+// token is a comma separated list of api keys, empty token sends requests without key at the anonymous rate
 func NewEtherscanAPI(token string, options ...func(x *EtherscanAPI)) *EtherscanAPI {
 	rpc := &EtherscanAPI{
-		tokens: strings.Split(token, ","),
+		keys:   newKeyPool(strings.Split(token, ","), 5),
 		url:    "https://api.etherscan.io/api",
 		client: http.DefaultClient,
+		retry:  etherscanRetryPolicy(),
+
+		batchConcurrency: 4,
 	}
+	rpc.backgroundRPC = backgroundRPC{rpc}
 	for _, option := range options {
 		option(rpc)
 	}
@@ -36,64 +101,221 @@ func (x *EtherscanAPI) Debug(debug bool) {
 	x.debug = debug
 }
 
+// RateLimitUsage returns usage of rate limit, zero when client is not rate limited
+func (x *EtherscanAPI) RateLimitUsage() RateLimitUsage {
+	return x.limiter.stat()
+}
+
+// KeyStatus returns usage of every api key
+func (x *EtherscanAPI) KeyStatus() []EtherscanKeyStatus {
+	return x.keys.status()
+}
+
 func (x *EtherscanAPI) String() string {
 	return "etherscan"
 }
 
-func (x *EtherscanAPI) call(method string, target interface{}, params ...interface{}) error {
-	result, err := x.Call(method, params)
+func (x *EtherscanAPI) call(ctx context.Context, method string, target interface{}, params ...interface{}) error {
+	result, err := x.CallContext(ctx, method, params...)
 	if err != nil {
 		return err
 	}
 
-	if target == nil {
-		return nil
+	return unmarshalResult(result, target)
+}
+
+// BatchCallContext emulates batch request with concurrent proxy calls spread over api keys,
+// responses are returned in the order of reqs. Json-rpc errors and unsupported methods are
+// returned as errors of responses, any other failure fails the batch
+func (x *EtherscanAPI) BatchCallContext(ctx context.Context, reqs []EthRequest) ([]EthResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resps := make([]EthResponse, len(reqs))
+	var once sync.Once
+	var batchErr error
+	sem := make(chan struct{}, x.batchConcurrency)
+	var wg sync.WaitGroup
+	for i := range reqs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			req := reqs[i]
+			resps[i] = EthResponse{ID: req.ID, Version: "2.0"}
+			result, err := x.CallContext(ctx, req.Method, req.Params...)
+			var ethErr EthError
+			switch {
+			case err == nil:
+				resps[i].Result = result
+			case errors.As(err, &ethErr):
+				resps[i].Error = &ethErr
+			case errors.Is(err, ErrNotSupported):
+				resps[i].Error = &EthError{Code: CodeMethodNotFound, Message: err.Error()}
+			default:
+				once.Do(func() {
+					batchErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if batchErr != nil {
+		return nil, batchErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
+	return resps, nil
+}
 
-	if bytes.Equal(result, []byte("null")) {
-		return fmt.Errorf("result null")
+// etherscanRetryPolicy retries failed calls with next api key
+func etherscanRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 6,
+		MinBackoff:  300 * time.Millisecond,
+		MaxBackoff:  300 * time.Millisecond,
+		Retryable:   etherscanRetryable,
 	}
-	return json.Unmarshal(result, target)
 }
 
-//batch request
-func (x *EtherscanAPI) BatchCall(reqs []EthRequest) ([]EthResponse, error) {
-	return nil, fmt.Errorf("TODO")
+// etherscanRetryable reports if err is transient or rejected the api key, 403 or status "0" is returned for exhausted key.
+// Key pool failing with every key disabled is not retried
+func etherscanRetryable(err error) bool {
+	return etherscanKeyRejected(err) || IsRetryable(err)
+}
+
+// etherscanKeyRejected reports if etherscan rejected the api key of request, 403 or status "0" of exhausted or invalid key
+func etherscanKeyRejected(err error) bool {
+	if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == http.StatusForbidden {
+		return true
+	}
+	etherscanErr, ok := err.(*EtherscanError)
+	return ok && etherscanErr.keyCooldown() > 0
 }
 
-// Call returns raw response of method call
-func (x *EtherscanAPI) Call(method string, params ...interface{}) (json.RawMessage, error) {
-	retry := 0
+// etherscanProxyParams - query names of positional json-rpc params of proxy module actions
+var etherscanProxyParams = map[string][]string{
+	"eth_blockNumber":                         {},
+	"eth_gasPrice":                            {},
+	"eth_getBlockByNumber":                    {"tag", "boolean"},
+	"eth_getUncleByBlockNumberAndIndex":       {"tag", "index"},
+	"eth_getBlockTransactionCountByNumber":    {"tag"},
+	"eth_getTransactionByHash":                {"txhash"},
+	"eth_getTransactionByBlockNumberAndIndex": {"tag", "index"},
+	"eth_getTransactionCount":                 {"address", "tag"},
+	"eth_getTransactionReceipt":               {"txhash"},
+	"eth_getStorageAt":                        {"address", "position", "tag"},
+	"eth_getCode":                             {"address", "tag"},
+	"eth_call":                                {"to", "data", "tag"},
+	"eth_estimateGas":                         {"data", "to", "value", "gas", "gasPrice"},
+	"eth_sendRawTransaction":                  {"hex"},
+}
 
-retry:
-	tokenIndex := retry % len(x.tokens)
-	token := x.tokens[tokenIndex]
+// proxyQuery encodes json-rpc method call as proxy module query
+func proxyQuery(method string, params []interface{}) (url.Values, error) {
+	names, ok := etherscanProxyParams[method]
+	if !ok {
+		return nil, fmt.Errorf("etherscan proxy %s: %w", method, ErrNotSupported)
+	}
+	if len(params) > len(names) {
+		return nil, fmt.Errorf("etherscan proxy %s takes %d params, got %d", method, len(names), len(params))
+	}
 
-	if retry > 5 {
-		return nil, fmt.Errorf("etherscan retry failed")
+	query := url.Values{}
+	query.Set("module", "proxy")
+	query.Set("action", method)
+	for i, param := range params {
+		query.Set(names[i], fmt.Sprint(param))
 	}
-	retry++
+	return query, nil
+}
 
-	req, err := http.NewRequest("GET", x.url, nil)
+// CallContext returns raw response of proxy module method call,
+// positional params are sent as the query params of the method.
+// eth_getBalance and eth_getLogs are served by account and logs modules,
+// eth_call and eth_estimateGas are sent with the supported fields of call message
+func (x *EtherscanAPI) CallContext(ctx context.Context, method string, params ...interface{}) (json.RawMessage, error) {
+	switch method {
+	case "eth_getBalance":
+		return x.getBalance(ctx, params)
+	case "eth_getLogs":
+		return x.getLogs(ctx, params)
+	case "eth_call", "eth_estimateGas":
+		return x.proxyCall(ctx, method, params)
+	}
+
+	query, err := proxyQuery(method, params)
 	if err != nil {
 		return nil, err
 	}
-	q := req.URL.Query()
-	q.Add("module", "proxy")
-	q.Add("action", method)
-	q.Add("apikey", token)
 
-	if params[0] != nil {
-		m, ok := params[0].(map[string]string)
-		if ok {
-			for k, v := range m {
-				fmt.Println(k + v)
+	return x.get(ctx, query)
+}
+
+// query returns raw result of module action, empty result list is not an error
+func (x *EtherscanAPI) query(ctx context.Context, module, action string, query url.Values) (json.RawMessage, error) {
+	query.Set("module", module)
+	query.Set("action", action)
+
+	return x.get(ctx, query)
+}
+
+// get sends query with api key of key pool and decodes response envelope,
+// failed requests are retried with next api key
+func (x *EtherscanAPI) get(ctx context.Context, query url.Values) (json.RawMessage, error) {
+	policy := x.retry
+	if query.Get("action") == "eth_sendRawTransaction" {
+		// resent transaction is rejected as already known if the failed attempt reached the node
+		policy = policy.restrict(func(err error) bool {
+			return isThrottled(err) || etherscanKeyRejected(err)
+		})
+	}
+
+	var result json.RawMessage
+	err := policy.do(ctx, func(attempt int) error {
+		if x.limiter != nil {
+			if err := x.limiter.wait(ctx, 1); err != nil {
+				return err
 			}
 		}
+		key, err := x.keys.acquire(ctx)
+		if err != nil {
+			return err
+		}
+		if key.token != "" {
+			query.Set("apikey", key.token)
+		}
+
+		data, err := x.send(ctx, query)
+		if err == nil {
+			result, err = decodeEtherscan(query.Get("module"), query.Get("action"), data)
+		}
+		x.keys.release(key, err)
+		return err
+	})
+	return result, err
+}
+
+func (x *EtherscanAPI) send(ctx context.Context, query url.Values) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", x.url+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
 	}
-	req.URL.RawQuery = q.Encode()
 
-	req.Header.Set("Content-Type", "application/json")
 	response, err := x.client.Do(req)
 	if response != nil {
 		defer response.Body.Close()
@@ -102,15 +324,11 @@ retry:
 		return nil, err
 	}
 
-	//check 403 to change apikey and retry
-	if response.StatusCode == 403 {
-		fmt.Printf("etherscan response 403, retrying %d...\n", retry)
-		time.Sleep(3e8)
-		goto retry
-	}
-
 	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("http status code error %d", response.StatusCode)
+		if x.debug {
+			fmt.Printf("etherscan response %d\n", response.StatusCode)
+		}
+		return nil, newHTTPError(response)
 	}
 
 	data, err := ioutil.ReadAll(response.Body)
@@ -119,203 +337,438 @@ retry:
 	}
 
 	if x.debug {
-		fmt.Printf("request %s %s response %s\n", method, q.Encode(), data)
+		fmt.Printf("request %s response %s\n", query.Get("action"), data)
 	}
+	return data, nil
+}
 
-	resp := new(EthResponse)
-	if err := json.Unmarshal(data, resp); err != nil {
-		return nil, err
-	}
+// Web3ClientVersionContext returns the current client version.
+func (x *EtherscanAPI) Web3ClientVersionContext(ctx context.Context) (string, error) {
+	return "", ErrNotSupported
+}
 
-	if resp.Error != nil {
-		return nil, *resp.Error
+// etherscanNetworks - network ids of etherscan api hosts
+var etherscanNetworks = map[string]string{
+	"api.etherscan.io":         "1",
+	"api-goerli.etherscan.io":  "5",
+	"api-sepolia.etherscan.io": "11155111",
+	"api-holesky.etherscan.io": "17000",
+}
+
+// NetVersionContext returns the current network protocol version.
+// Etherscan serves a single network per host, the version is known by api host
+func (x *EtherscanAPI) NetVersionContext(ctx context.Context) (string, error) {
+	u, err := url.Parse(x.url)
+	if err != nil {
+		return "", err
 	}
+	if version, ok := etherscanNetworks[u.Hostname()]; ok {
+		return version, nil
+	}
+	return "", ErrNotSupported
+}
 
-	return resp.Result, nil
+// NetListeningContext returns true if client is actively listening for network connections.
+// Etherscan is listening when it answers requests
+func (x *EtherscanAPI) NetListeningContext(ctx context.Context) (bool, error) {
+	if _, err := x.EthBlockNumberContext(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
+// NetPeerCountContext returns number of peers currently connected to the client.
+func (x *EtherscanAPI) NetPeerCountContext(ctx context.Context) (int, error) {
+	return 0, ErrNotSupported
 }
 
-// Web3ClientVersion returns the current client version.
-func (x *EtherscanAPI) Web3ClientVersion() (string, error) {
-	return "", fmt.Errorf("TODO")
+// EthProtocolVersionContext returns the current ethereum protocol version.
+func (x *EtherscanAPI) EthProtocolVersionContext(ctx context.Context) (string, error) {
+	return "", ErrNotSupported
 }
 
-// NetVersion returns the current network protocol version.
-func (x *EtherscanAPI) NetVersion() (string, error) {
-	return "", fmt.Errorf("TODO")
+// EthSyncingContext returns an object with data about the sync status or false.
+// Etherscan serves indexed chain only, it is never syncing
+func (x *EtherscanAPI) EthSyncingContext(ctx context.Context) (*Syncing, error) {
+	return &Syncing{IsSyncing: false}, nil
 }
 
-// NetListening returns true if client is actively listening for network connections.
-func (x *EtherscanAPI) NetListening() (bool, error) {
-	return false, fmt.Errorf("TODO")
+// EthGasPriceContext returns the current price per gas in wei.
+func (x *EtherscanAPI) EthGasPriceContext(ctx context.Context) (big.Int, error) {
+	result, err := x.CallContext(ctx, "eth_gasPrice")
+	if err != nil {
+		return big.Int{}, err
+	}
+
+	return decodeHexBig(result)
 }
 
-// NetPeerCount returns number of peers currently connected to the client.
-func (x *EtherscanAPI) NetPeerCount() (int, error) {
-	return 0, fmt.Errorf("TODO")
+// EthBlockNumberContext returns the number of most recent block.
+func (x *EtherscanAPI) EthBlockNumberContext(ctx context.Context) (int, error) {
+	result, err := x.CallContext(ctx, "eth_blockNumber")
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeHexInt(result)
 }
 
-// EthProtocolVersion returns the current ethereum protocol version.
-func (x *EtherscanAPI) EthProtocolVersion() (string, error) {
-	return "", fmt.Errorf("TODO")
+// blockNo converts hex block number or tag to decimal block number of account and logs modules,
+// which know no pending, safe or finalized block
+func blockNo(block string) (string, error) {
+	switch block {
+	case "", "latest":
+		return "latest", nil
+	case "earliest":
+		return "0", nil
+	case "pending", "safe", "finalized":
+		return "", fmt.Errorf("etherscan %s block: %w", block, ErrNotSupported)
+	}
+
+	number, err := ParseInt(block)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(number), nil
 }
 
-// EthSyncing returns an object with data about the sync status or false.
-func (x *EtherscanAPI) EthSyncing() (*Syncing, error) {
-	return nil, fmt.Errorf("TODO")
+// getBalance returns hex encoded balance of eth_getBalance params,
+// balance of past block is served by balancehistory, which requires etherscan pro api key
+func (x *EtherscanAPI) getBalance(ctx context.Context, params []interface{}) (json.RawMessage, error) {
+	if len(params) == 0 || len(params) > 2 {
+		return nil, fmt.Errorf("etherscan eth_getBalance takes 2 params, got %d", len(params))
+	}
+	block := "latest"
+	if len(params) == 2 {
+		block = fmt.Sprint(params[1])
+	}
+	number, err := blockNo(block)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("address", fmt.Sprint(params[0]))
+	action := "balance"
+	if number == "latest" {
+		query.Set("tag", "latest")
+	} else {
+		action = "balancehistory"
+		query.Set("blockno", number)
+	}
+
+	var balance string
+	result, err := x.query(ctx, "account", action, query)
+	if err == nil {
+		err = unmarshalResult(result, &balance)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := ParseBigInt(balance)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(BigToHex(value))
 }
 
-// EthGasPrice returns the current price per gas in wei.
-func (x *EtherscanAPI) EthGasPrice() (big.Int, error) {
-	var response string
-	if err := x.call("eth_gasPrice", &response, nil); err != nil {
+// EthGetBalanceContext returns the balance of the account of given address in wei.
+func (x *EtherscanAPI) EthGetBalanceContext(ctx context.Context, address string, block BlockNumberOrTag) (big.Int, error) {
+	result, err := x.CallContext(ctx, "eth_getBalance", address, block)
+	if err != nil {
 		return big.Int{}, err
 	}
 
-	return ParseBigInt(response)
+	return decodeHexBig(result)
 }
 
-// EthBlockNumber returns the number of most recent block.
-func (x *EtherscanAPI) EthBlockNumber() (int, error) {
-	var response string
-	if err := x.call("eth_blockNumber", &response, nil); err != nil {
+// EthGetStorageAtContext returns the value from a storage position at a given address.
+func (x *EtherscanAPI) EthGetStorageAtContext(ctx context.Context, data string, position int, tag BlockNumberOrTag) (string, error) {
+	var result string
+
+	err := x.call(ctx, "eth_getStorageAt", &result, data, IntToHex(position), tag)
+	return result, err
+}
+
+// EthGetTransactionCountContext returns the number of transactions sent from an address.
+func (x *EtherscanAPI) EthGetTransactionCountContext(ctx context.Context, address string, block BlockNumberOrTag) (int, error) {
+	result, err := x.CallContext(ctx, "eth_getTransactionCount", address, block)
+	if err != nil {
 		return 0, err
 	}
 
-	return ParseInt(response)
+	return decodeHexInt(result)
 }
 
-// EthGetBalance returns the balance of the account of given address in wei.
-func (x *EtherscanAPI) EthGetBalance(address, block string) (big.Int, error) {
-	return big.Int{}, fmt.Errorf("TODO")
+// proxyCall sends call message of eth_call or eth_estimateGas as the query params of proxy module action,
+// a set message field without query param fails with ErrNotSupported instead of being dropped
+func (x *EtherscanAPI) proxyCall(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+	names := etherscanProxyParams[method]
+	maxParams := 1
+	if names[len(names)-1] == "tag" {
+		maxParams = 2
+	}
+	if len(params) == 0 || len(params) > maxParams {
+		return nil, fmt.Errorf("etherscan %s takes %d params, got %d", method, maxParams, len(params))
+	}
+
+	data, err := json.Marshal(params[0])
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]string{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("module", "proxy")
+	query.Set("action", method)
+	for _, name := range names {
+		if name == "tag" {
+			if len(params) == 2 {
+				query.Set(name, fmt.Sprint(params[1]))
+			}
+			continue
+		}
+		if value, ok := fields[name]; ok {
+			query.Set(name, value)
+			delete(fields, name)
+		}
+	}
+	for name, value := range fields {
+		if value != "" {
+			return nil, fmt.Errorf("etherscan %s with %s: %w", method, name, ErrNotSupported)
+		}
+	}
+
+	return x.get(ctx, query)
 }
 
-// EthGetStorageAt returns the value from a storage position at a given address.
-func (x *EtherscanAPI) EthGetStorageAt(data string, position int, tag string) (string, error) {
+// EthCallContext executes a new message call immediately without creating a transaction on the block chain.
+// Etherscan takes only to and data of the message, a message with from, gas, gas price or value fails with ErrNotSupported
+func (x *EtherscanAPI) EthCallContext(ctx context.Context, msg CallMsg, block BlockNumberOrTag) (string, error) {
 	var result string
 
-	params := map[string]string{
-		"address": data,
-		"position": IntToHex(position),
-		"tag": tag,
-	}
-	err := x.call("eth_getStorageAt", &result, params)
+	err := x.call(ctx, "eth_call", &result, msg, block)
 	return result, err
 }
 
-// EthGetTransactionCount returns the number of transactions sent from an address.
-func (x *EtherscanAPI) EthGetTransactionCount(address, block string) (int, error) {
-	var response string
-
-	params := map[string]string{
-		"address": address,
-		"tag": "latest",
-	}
-	if err := x.call("eth_getTransactionCount", &response, params); err != nil {
+// EthEstimateGasContext makes a call and returns the amount of gas it takes, the call is not added to the block chain.
+// Etherscan takes no sender, a message with from fails with ErrNotSupported
+func (x *EtherscanAPI) EthEstimateGasContext(ctx context.Context, msg CallMsg) (int, error) {
+	result, err := x.CallContext(ctx, "eth_estimateGas", msg)
+	if err != nil {
 		return 0, err
 	}
 
-	return ParseInt(response)
+	return decodeHexInt(result)
 }
 
-// EthGetBlockTransactionCountByNumber returns the number of transactions in a block from a block matching the given block
-func (x *EtherscanAPI) EthGetBlockTransactionCountByNumber(number int) (int, error) {
-	var response string
+// EthGetCodeContext returns code at a given address.
+func (x *EtherscanAPI) EthGetCodeContext(ctx context.Context, address string, block BlockNumberOrTag) (string, error) {
+	var code string
 
-	params := map[string]string{
-		"tag": IntToHex(number),
-	}
+	err := x.call(ctx, "eth_getCode", &code, address, block)
+	return code, err
+}
+
+// EthSendRawTransactionContext creates new message call transaction or a contract creation for signed transactions, it returns the transaction hash.
+func (x *EtherscanAPI) EthSendRawTransactionContext(ctx context.Context, data string) (string, error) {
+	var hash string
+
+	err := x.call(ctx, "eth_sendRawTransaction", &hash, data)
+	return hash, err
+}
 
-	if err := x.call("eth_getBlockTransactionCountByNumber", &response, params); err != nil {
+// EthGetBlockTransactionCountByNumberContext returns the number of transactions in a block from a block matching the given block
+func (x *EtherscanAPI) EthGetBlockTransactionCountByNumberContext(ctx context.Context, number BlockNumberOrTag) (int, error) {
+	result, err := x.CallContext(ctx, "eth_getBlockTransactionCountByNumber", number)
+	if err != nil {
 		return 0, err
 	}
 
-	return ParseInt(response)
+	return decodeHexInt(result)
 }
 
-func (x *EtherscanAPI) EthGetBlockByNumber(number int, withTransactions bool) (*Block, error) {
-	var response ProxyBlock
-	if withTransactions {
-		response = new(ProxyBlockWithTransactions)
-	} else {
-		response = new(ProxyBlockWithoutTransactions)
+// EthGetBlockTransactionCountByHashContext returns the number of transactions in a block from a block matching the given block hash.
+func (x *EtherscanAPI) EthGetBlockTransactionCountByHashContext(ctx context.Context, hash string) (int, error) {
+	result, err := x.CallContext(ctx, "eth_getBlockTransactionCountByHash", hash)
+	if err != nil {
+		return 0, err
 	}
 
-	params := map[string]string{
-		"tag": IntToHex(number),
-		"boolean": fmt.Sprintf("%v", withTransactions),
-	}
+	return decodeHexInt(result)
+}
 
-	err := x.call("eth_getBlockByNumber", response, params)
+// EthGetBlockByNumberContext returns information about a block by block number.
+func (x *EtherscanAPI) EthGetBlockByNumberContext(ctx context.Context, number BlockNumberOrTag, withTransactions bool) (*Block, error) {
+	result, err := x.CallContext(ctx, "eth_getBlockByNumber", number, withTransactions)
 	if err != nil {
 		return nil, err
 	}
-	block := response.ToBlock()
-	if len(block.Hash) == 0 {
-		return nil, fmt.Errorf("block not found")
-	}
 
-	return &block, nil
+	return decodeBlock(result, withTransactions)
 }
 
-func (x *EtherscanAPI) EthGetUncleByBlockNumberAndIndex(number int, pos int) (*Block, error) {
-	return nil, fmt.Errorf("TODO")
-}
+// EthGetBlockByHashContext returns information about a block by block hash.
+func (x *EtherscanAPI) EthGetBlockByHashContext(ctx context.Context, hash string, withTransactions bool) (*Block, error) {
+	result, err := x.CallContext(ctx, "eth_getBlockByHash", hash, withTransactions)
+	if err != nil {
+		return nil, err
+	}
 
-// EthGetTransactionByHash returns the information about a transaction requested by transaction hash.
-func (x *EtherscanAPI) EthGetTransactionByHash(hash string) (*Transaction, error) {
-	transaction := new(Transaction)
+	return decodeBlock(result, withTransactions)
+}
 
-	params := map[string]string{
-		"txhash": hash,
+func (x *EtherscanAPI) EthGetUncleByBlockNumberAndIndexContext(ctx context.Context, number BlockNumberOrTag, pos int) (*Block, error) {
+	result, err := x.CallContext(ctx, "eth_getUncleByBlockNumberAndIndex", number, IntToHex(pos))
+	if err != nil {
+		return nil, err
 	}
 
-	err := x.call("eth_getTransactionByHash", transaction, params)
-	if len(transaction.Hash) == 0 {
-		return nil, fmt.Errorf("tx not found")
+	return decodeUncleBlock(result)
+}
+
+// EthGetUncleCountByBlockNumberContext returns the number of uncles in a block from a block matching the given block number.
+func (x *EtherscanAPI) EthGetUncleCountByBlockNumberContext(ctx context.Context, number BlockNumberOrTag) (int, error) {
+	result, err := x.CallContext(ctx, "eth_getUncleCountByBlockNumber", number)
+	if err != nil {
+		return 0, err
 	}
-	return transaction, err
+
+	return decodeHexInt(result)
 }
 
-// EthGetTransactionByBlockNumberAndIndex returns information about a transaction by block number and transaction index position.
-func (x *EtherscanAPI) EthGetTransactionByBlockNumberAndIndex(blockNumber, transactionIndex int) (*Transaction, error) {
-	transaction := new(Transaction)
+// EthGetUncleCountByBlockHashContext returns the number of uncles in a block from a block matching the given block hash.
+func (x *EtherscanAPI) EthGetUncleCountByBlockHashContext(ctx context.Context, hash string) (int, error) {
+	result, err := x.CallContext(ctx, "eth_getUncleCountByBlockHash", hash)
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeHexInt(result)
+}
 
-	params := map[string]string{
-		"tag": IntToHex(blockNumber),
-		"index": IntToHex(transactionIndex),
+// EthGetUncleByBlockHashAndIndexContext returns information about a uncle of a block by hash and uncle index position.
+func (x *EtherscanAPI) EthGetUncleByBlockHashAndIndexContext(ctx context.Context, hash string, pos int) (*Block, error) {
+	result, err := x.CallContext(ctx, "eth_getUncleByBlockHashAndIndex", hash, IntToHex(pos))
+	if err != nil {
+		return nil, err
 	}
 
-	err := x.call("eth_getTransactionByBlockNumberAndIndex", transaction, params)
-	if len(transaction.Hash) == 0 {
-		return nil, fmt.Errorf("tx not found")
+	return decodeUncleBlock(result)
+}
+
+// EthGetTransactionByHashContext returns the information about a transaction requested by transaction hash.
+func (x *EtherscanAPI) EthGetTransactionByHashContext(ctx context.Context, hash string) (*Transaction, error) {
+	result, err := x.CallContext(ctx, "eth_getTransactionByHash", hash)
+	if err != nil {
+		return nil, err
 	}
-	return transaction, err
+
+	return decodeTransaction(result)
 }
 
-// EthGetTransactionReceipt returns the receipt of a transaction by transaction hash.
-// Note That the receipt is not available for pending transactions.
-func (x *EtherscanAPI) EthGetTransactionReceipt(hash string) (*TransactionReceipt, error) {
-	transactionReceipt := new(TransactionReceipt)
+// EthGetTransactionByBlockNumberAndIndexContext returns information about a transaction by block number and transaction index position.
+func (x *EtherscanAPI) EthGetTransactionByBlockNumberAndIndexContext(ctx context.Context, blockNumber BlockNumberOrTag, transactionIndex int) (*Transaction, error) {
+	result, err := x.CallContext(ctx, "eth_getTransactionByBlockNumberAndIndex", blockNumber, IntToHex(transactionIndex))
+	if err != nil {
+		return nil, err
+	}
 
-	params := map[string]string{
-		"txhash": hash,
+	return decodeTransaction(result)
+}
+
+// EthGetTransactionByBlockHashAndIndexContext returns information about a transaction by block hash and transaction index position.
+func (x *EtherscanAPI) EthGetTransactionByBlockHashAndIndexContext(ctx context.Context, blockHash string, transactionIndex int) (*Transaction, error) {
+	result, err := x.CallContext(ctx, "eth_getTransactionByBlockHashAndIndex", blockHash, IntToHex(transactionIndex))
+	if err != nil {
+		return nil, err
 	}
 
-	err := x.call("eth_getTransactionReceipt", transactionReceipt, params)
+	return decodeTransaction(result)
+}
+
+// EthGetTransactionReceiptContext returns the receipt of a transaction by transaction hash.
+// Note That the receipt is not available for pending transactions.
+func (x *EtherscanAPI) EthGetTransactionReceiptContext(ctx context.Context, hash string) (*TransactionReceipt, error) {
+	result, err := x.CallContext(ctx, "eth_getTransactionReceipt", hash)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(transactionReceipt.TransactionHash) == 0 {
-		return nil, fmt.Errorf("receipt not found")
+	return decodeTransactionReceipt(result)
+}
+
+// logsQuery encodes filter as logs module query, etherscan takes a single address
+// and a single topic per position, topics are matched with and operator
+func logsQuery(params FilterParams) (url.Values, error) {
+	query := url.Values{}
+
+	for name, block := range map[string]string{"fromBlock": params.FromBlock, "toBlock": params.ToBlock} {
+		number, err := blockNo(block)
+		if err != nil {
+			return nil, err
+		}
+		query.Set(name, number)
+	}
+
+	switch len(params.Address) {
+	case 0:
+	case 1:
+		query.Set("address", params.Address[0])
+	default:
+		return nil, fmt.Errorf("etherscan logs of %d addresses: %w", len(params.Address), ErrNotSupported)
+	}
+
+	var positions []int
+	for i, topics := range params.Topics {
+		switch len(topics) {
+		case 0:
+			continue
+		case 1:
+			query.Set(fmt.Sprintf("topic%d", i), topics[0])
+			positions = append(positions, i)
+		default:
+			return nil, fmt.Errorf("etherscan logs of %d alternative topics: %w", len(topics), ErrNotSupported)
+		}
+	}
+	for i := range positions {
+		for _, j := range positions[i+1:] {
+			query.Set(fmt.Sprintf("topic%d_%d_opr", positions[i], j), "and")
+		}
+	}
+	return query, nil
+}
+
+// getLogs returns raw logs of eth_getLogs filter param
+func (x *EtherscanAPI) getLogs(ctx context.Context, params []interface{}) (json.RawMessage, error) {
+	if len(params) != 1 {
+		return nil, fmt.Errorf("etherscan eth_getLogs takes 1 param, got %d", len(params))
+	}
+	filter, ok := params[0].(FilterParams)
+	if !ok {
+		data, err := json.Marshal(params[0])
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &filter); err != nil {
+			return nil, err
+		}
 	}
 
-	return transactionReceipt, nil
+	query, err := logsQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+	return x.query(ctx, "logs", "getLogs", query)
 }
 
-// EthGetLogs returns an array of all logs matching a given filter object.
-func (x *EtherscanAPI) EthGetLogs(params FilterParams) ([]Log, error) {
+// EthGetLogsContext returns an array of all logs matching a given filter object.
+func (x *EtherscanAPI) EthGetLogsContext(ctx context.Context, params FilterParams) ([]Log, error) {
 	var logs []Log
-	return logs, fmt.Errorf("TODO")
-}
\ No newline at end of file
+	err := x.call(ctx, "eth_getLogs", &logs, params)
+	return logs, err
+}
@@ -0,0 +1,378 @@
+package ethrpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newEtherscanServer returns etherscan stand-in answering queries by module/action,
+// queries are sent to got
+func newEtherscanServer(responses map[string]string, got chan<- url.Values) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if got != nil {
+			got <- query
+		}
+		response, ok := responses[query.Get("module")+"/"+query.Get("action")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(response))
+	}))
+}
+
+func TestEtherscanProxyParams(t *testing.T) {
+	got := make(chan url.Values, 1)
+	server := newEtherscanServer(map[string]string{
+		"proxy/eth_getTransactionByHash":  `{"jsonrpc":"2.0","id":1,"result":{"hash":"0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1","nonce":"0x1","blockNumber":"0x5daf3b","transactionIndex":"0x41","value":"0xf3dbb76162000","gas":"0xc350","gasPrice":"0x4a817c800","input":"0x"}}`,
+		"proxy/eth_getBlockByNumber":      `{"jsonrpc":"2.0","id":1,"result":null}`,
+		"proxy/eth_getTransactionCount":   `{"jsonrpc":"2.0","id":1,"result":"0x20"}`,
+		"proxy/eth_getTransactionReceipt": `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"invalid argument 0"}}`,
+	}, got)
+	defer server.Close()
+
+	rpc := NewEtherscanAPI("key", EtherscanURL(server.URL))
+
+	tx, err := rpc.EthGetTransactionByHash("0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1")
+	require.Nil(t, err)
+	require.Equal(t, 6139707, *tx.BlockNumber)
+	query := <-got
+	require.Equal(t, "0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1", query.Get("txhash"))
+	require.Equal(t, "key", query.Get("apikey"))
+
+	_, err = rpc.EthGetBlockByNumber(BlockNumber(100), true)
+	require.True(t, errors.Is(err, ErrNotFound))
+	query = <-got
+	require.Equal(t, "0x64", query.Get("tag"))
+	require.Equal(t, "true", query.Get("boolean"))
+
+	count, err := rpc.EthGetTransactionCount("0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf", "0x10")
+	require.Nil(t, err)
+	require.Equal(t, 32, count)
+	query = <-got
+	require.Equal(t, "0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf", query.Get("address"))
+	require.Equal(t, "0x10", query.Get("tag"))
+
+	_, err = rpc.EthGetTransactionReceipt("0x0")
+	require.Equal(t, EthError{Code: -32602, Message: "invalid argument 0"}, err)
+	<-got
+
+	_, err = rpc.Call("eth_chainId")
+	require.True(t, errors.Is(err, ErrNotSupported))
+}
+
+func TestEtherscanBalance(t *testing.T) {
+	got := make(chan url.Values, 1)
+	server := newEtherscanServer(map[string]string{
+		"account/balance":        `{"status":"1","message":"OK","result":"40891626854930000000999"}`,
+		"account/balancehistory": `{"status":"1","message":"OK","result":"610538078574759898951277"}`,
+	}, got)
+	defer server.Close()
+
+	rpc := NewEtherscanAPI("key", EtherscanURL(server.URL))
+
+	balance, err := rpc.EthGetBalance("0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae", "latest")
+	require.Nil(t, err)
+	require.Equal(t, "40891626854930000000999", balance.String())
+	query := <-got
+	require.Equal(t, "0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae", query.Get("address"))
+	require.Equal(t, "latest", query.Get("tag"))
+
+	balance, err = rpc.EthGetBalance("0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae", "0x7a120")
+	require.Nil(t, err)
+	require.Equal(t, "610538078574759898951277", balance.String())
+	query = <-got
+	require.Equal(t, "500000", query.Get("blockno"))
+
+	// account module has no pending, safe or finalized block
+	for _, block := range []BlockNumberOrTag{BlockPending, BlockSafe, BlockFinalized} {
+		_, err = rpc.EthGetBalance("0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae", block)
+		require.True(t, errors.Is(err, ErrNotSupported))
+	}
+	require.Len(t, got, 0)
+}
+
+func TestEtherscanLogs(t *testing.T) {
+	got := make(chan url.Values, 1)
+	server := newEtherscanServer(map[string]string{
+		"logs/getLogs": `{"status":"1","message":"OK","result":[{"address":"0x33990122638b9132ca29c723bdf037f1a891a70c","topics":["0xf63780e752c6a54a94fc52715dbc5518a3b4c3c2833d301a204226548a2a8545","0x0000000000000000000000000000000000000000000000000000000000000001"],"data":"0x","blockNumber":"0x5c958","blockHash":"0x8c16ed4fa1d4c9a7cd2ae3f4c6d2d9e4c48e2f1b3c2a1b0f9e8d7c6b5a4f3e2d","timeStamp":"0x561d688c","gasPrice":"0xba43b7400","gasUsed":"0x10682","logIndex":"0x","transactionHash":"0x0b03498648ae2da924f961dda00dc6bb0a8df15519262b7e012b7d67f4bb7e83","transactionIndex":"0x"}]}`,
+	}, got)
+	defer server.Close()
+
+	rpc := NewEtherscanAPI("key", EtherscanURL(server.URL))
+
+	logs, err := rpc.EthGetLogs(FilterParams{
+		FromBlock: "0x5c958",
+		ToBlock:   "latest",
+		Address:   []string{"0x33990122638b9132ca29c723bdf037f1a891a70c"},
+		Topics: [][]string{
+			{"0xf63780e752c6a54a94fc52715dbc5518a3b4c3c2833d301a204226548a2a8545"},
+			nil,
+			{"0x0000000000000000000000000000000000000000000000000000000000000001"},
+		},
+	})
+	require.Nil(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, 379224, logs[0].BlockNumber)
+	require.Equal(t, 0, logs[0].LogIndex)
+
+	query := <-got
+	require.Equal(t, "379224", query.Get("fromBlock"))
+	require.Equal(t, "latest", query.Get("toBlock"))
+	require.Equal(t, "0x33990122638b9132ca29c723bdf037f1a891a70c", query.Get("address"))
+	require.Equal(t, "0xf63780e752c6a54a94fc52715dbc5518a3b4c3c2833d301a204226548a2a8545", query.Get("topic0"))
+	require.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000001", query.Get("topic2"))
+	require.Equal(t, "and", query.Get("topic0_2_opr"))
+
+	_, err = rpc.EthGetLogs(FilterParams{Address: []string{"0x1", "0x2"}})
+	require.True(t, errors.Is(err, ErrNotSupported))
+	_, err = rpc.EthGetLogs(FilterParams{FromBlock: "0x5c958", ToBlock: "finalized"})
+	require.True(t, errors.Is(err, ErrNotSupported))
+}
+
+func TestEtherscanNetwork(t *testing.T) {
+	version, err := NewEtherscanAPI("key").NetVersion()
+	require.Nil(t, err)
+	require.Equal(t, "1", version)
+
+	version, err = NewEtherscanAPI("key", EtherscanURL("https://api-sepolia.etherscan.io/api")).NetVersion()
+	require.Nil(t, err)
+	require.Equal(t, "11155111", version)
+
+	syncing, err := NewEtherscanAPI("key").EthSyncing()
+	require.Nil(t, err)
+	require.False(t, syncing.IsSyncing)
+}
+
+func TestEtherscanBatchCall(t *testing.T) {
+	got := make(chan url.Values, 10)
+	server := newEtherscanServer(map[string]string{
+		"proxy/eth_blockNumber":           `{"jsonrpc":"2.0","id":83,"result":"0x10"}`,
+		"proxy/eth_getTransactionReceipt": `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"invalid argument 0"}}`,
+		"account/balance":                 `{"status":"1","message":"OK","result":"1000000000000000000"}`,
+	}, got)
+	defer server.Close()
+
+	rpc := NewEtherscanAPI("a,b", EtherscanURL(server.URL))
+
+	batch := NewBatch(rpc)
+	number := batch.EthBlockNumber()
+	balance := batch.EthGetBalance("0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae", "latest")
+	receipt := batch.EthGetTransactionReceipt("0x0")
+	version := batch.Web3ClientVersion()
+	require.Nil(t, batch.Execute())
+
+	n, err := number.Result()
+	require.Nil(t, err)
+	require.Equal(t, 16, n)
+	b, err := balance.Result()
+	require.Nil(t, err)
+	require.Equal(t, "1000000000000000000", b.String())
+	_, err = receipt.Result()
+	require.Equal(t, EthError{Code: -32602, Message: "invalid argument 0"}, err)
+	_, err = version.Result()
+	require.True(t, errors.Is(err, ErrNotSupported))
+
+	// calls are spread over api keys
+	keys := map[string]int{}
+	for i := 0; i < 3; i++ {
+		keys[(<-got).Get("apikey")]++
+	}
+	require.Equal(t, map[string]int{"a": 2, "b": 1}, keys)
+
+	// failed request fails the batch
+	_, err = rpc.BatchCall([]EthRequest{NewEthRequest("eth_gasPrice")})
+	require.Equal(t, "http status code error 404", err.Error())
+}
+
+func TestEtherscanKeyPool(t *testing.T) {
+	got := make(chan url.Values, 20)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		got <- query
+		switch query.Get("apikey") {
+		case "limited":
+			w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Max rate limit reached"}`))
+		case "invalid":
+			w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Invalid API Key"}`))
+		default:
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+		}
+	}))
+	defer server.Close()
+
+	rpc := NewEtherscanAPI("limited,invalid,key1,key2", EtherscanURL(server.URL), EtherscanRetry(RetryPolicy{MaxAttempts: 3}))
+
+	// rejected keys are disabled, requests are sent round robin with enabled keys
+	var keys []string
+	for i := 0; i < 4; i++ {
+		_, err := rpc.EthBlockNumber()
+		require.Nil(t, err)
+	}
+	for len(got) > 0 {
+		keys = append(keys, (<-got).Get("apikey"))
+	}
+	require.Equal(t, []string{"limited", "invalid", "key1", "key2", "key1", "key2"}, keys)
+
+	status := rpc.KeyStatus()
+	require.Equal(t, "***ited", status[0].Key)
+	require.Equal(t, int64(1), status[0].Failures)
+	require.False(t, status[0].DisabledUntil.IsZero())
+	require.False(t, status[1].DisabledUntil.IsZero())
+	require.Equal(t, int64(2), status[2].Requests)
+	require.True(t, status[2].DisabledUntil.IsZero())
+
+	// every key is disabled
+	rpc = NewEtherscanAPI("invalid", EtherscanURL(server.URL))
+	_, err := rpc.EthBlockNumber()
+	require.Contains(t, err.Error(), "Invalid API Key")
+	require.False(t, errors.Is(err, ErrRateLimited))
+	_, err = rpc.EthBlockNumber()
+	require.Contains(t, err.Error(), "etherscan api keys disabled until")
+	<-got
+	require.Len(t, got, 0)
+}
+
+func TestEtherscanKeyRate(t *testing.T) {
+	server := newEtherscanServer(map[string]string{
+		"proxy/eth_blockNumber": `{"jsonrpc":"2.0","id":1,"result":"0x10"}`,
+	}, nil)
+	defer server.Close()
+
+	rpc := NewEtherscanAPI("a,b", EtherscanURL(server.URL), EtherscanKeyRate(10))
+
+	// burst of 10 per key, then 20 requests per second over both keys
+	start := time.Now()
+	for i := 0; i < 24; i++ {
+		_, err := rpc.EthBlockNumber()
+		require.Nil(t, err)
+	}
+	require.True(t, time.Since(start) >= 150*time.Millisecond)
+	require.Equal(t, int64(12), rpc.KeyStatus()[0].Requests)
+	require.Equal(t, int64(12), rpc.KeyStatus()[1].Requests)
+}
+
+func TestEtherscanCall(t *testing.T) {
+	got := make(chan url.Values, 1)
+	server := newEtherscanServer(map[string]string{
+		"proxy/eth_call":               `{"jsonrpc":"2.0","id":1,"result":"0x00000000000000000000000000000000000000000000000000000000000003e8"}`,
+		"proxy/eth_estimateGas":        `{"jsonrpc":"2.0","id":1,"result":"0x5208"}`,
+		"proxy/eth_sendRawTransaction": `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"already known"}}`,
+	}, got)
+	defer server.Close()
+
+	rpc := NewEtherscanAPI("key", EtherscanURL(server.URL))
+
+	result, err := rpc.EthCall(CallMsg{
+		To:   "0xdac17f958d2ee523a2206206994597c13d831ec7",
+		Data: "0x18160ddd",
+	}, "0x10")
+	require.Nil(t, err)
+	require.Equal(t, "0x00000000000000000000000000000000000000000000000000000000000003e8", result)
+	query := <-got
+	require.Equal(t, "0xdac17f958d2ee523a2206206994597c13d831ec7", query.Get("to"))
+	require.Equal(t, "0x18160ddd", query.Get("data"))
+	require.Equal(t, "0x10", query.Get("tag"))
+
+	// fields etherscan can not forward fail the call without request
+	for _, msg := range []CallMsg{
+		{From: "0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf", To: "0xdac17f958d2ee523a2206206994597c13d831ec7"},
+		{To: "0xdac17f958d2ee523a2206206994597c13d831ec7", Gas: 100000},
+		{To: "0xdac17f958d2ee523a2206206994597c13d831ec7", Value: big.NewInt(1)},
+	} {
+		_, err = rpc.EthCall(msg, "latest")
+		require.True(t, errors.Is(err, ErrNotSupported))
+	}
+	_, err = rpc.EthEstimateGas(CallMsg{From: "0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf", To: "0xdac17f958d2ee523a2206206994597c13d831ec7"})
+	require.True(t, errors.Is(err, ErrNotSupported))
+	require.Contains(t, err.Error(), "with from")
+	require.Len(t, got, 0)
+
+	gas, err := rpc.EthEstimateGas(CallMsg{To: "0xdac17f958d2ee523a2206206994597c13d831ec7", Gas: 100000, Value: big.NewInt(1000)})
+	require.Nil(t, err)
+	require.Equal(t, 21000, gas)
+	query = <-got
+	require.Equal(t, "eth_estimateGas", query.Get("action"))
+	require.Equal(t, "0x186a0", query.Get("gas"))
+	require.Equal(t, "0x3e8", query.Get("value"))
+	require.Equal(t, "", query.Get("tag"))
+
+	_, err = rpc.EthSendRawTransaction("0xf86c")
+	require.True(t, errors.Is(err, ErrAlreadyKnown))
+	require.Equal(t, "0xf86c", (<-got).Get("hex"))
+}
+
+func TestEtherscanEnvelope(t *testing.T) {
+	server := newEtherscanServer(map[string]string{
+		"proxy/eth_gasPrice":           `{"status":"0","message":"NOTOK","result":"Error! Missing Or invalid Module name"}`,
+		"proxy/eth_blockNumber":        `{"jsonrpc":"2.0","id":1,"result":"Max calls per sec rate limit reached (5/sec)"}`,
+		"account/txlist":               `{"status":"0","message":"No transactions found","result":[]}`,
+		"account/balance":              `{"status":"0","message":"NOTOK","result":"Error! Invalid address format"}`,
+		"account/balancehistory":       `{"status":"1","message":"OK","result":"1"`,
+		"contract/getcontractcreation": `{"status":"2","message":"OK","result":null}`,
+	}, nil)
+	defer server.Close()
+
+	rpc := NewEtherscanAPI("key", EtherscanURL(server.URL), EtherscanRetry(RetryPolicy{}))
+
+	// proxy call rejected with status "0"
+	_, err := rpc.EthGasPrice()
+	var etherscanErr *EtherscanError
+	require.True(t, errors.As(err, &etherscanErr))
+	require.Equal(t, "proxy", etherscanErr.Module)
+	require.Equal(t, "eth_gasPrice", etherscanErr.Action)
+	require.Equal(t, "NOTOK", etherscanErr.Message)
+	require.True(t, errors.Is(err, ErrNotSupported))
+
+	// proxy call answered with plain string result
+	_, err = rpc.EthBlockNumber()
+	require.True(t, errors.As(err, &etherscanErr))
+	require.Equal(t, "Max calls per sec rate limit reached (5/sec)", etherscanErr.Result)
+	require.True(t, errors.Is(err, ErrRateLimited))
+
+	// no records is an empty result
+	txs, err := rpc.AccountTransactions("0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae", AccountQuery{})
+	require.Nil(t, err)
+	require.Len(t, txs, 0)
+
+	_, err = rpc.EthGetBalance("0x0", "latest")
+	require.Equal(t, "etherscan account/balance: NOTOK Error! Invalid address format", err.Error())
+
+	_, err = rpc.EthGetBalance("0x0", "0x1")
+	require.Contains(t, err.Error(), "etherscan account/balancehistory invalid response")
+
+	_, err = rpc.ContractCreation("0x0")
+	require.True(t, errors.As(err, &etherscanErr))
+}
+
+func TestEtherscanKeyless(t *testing.T) {
+	got := make(chan url.Values, 2)
+	server := newEtherscanServer(map[string]string{
+		"proxy/eth_blockNumber": `{"jsonrpc":"2.0","id":1,"result":"0x10"}`,
+	}, got)
+	defer server.Close()
+
+	rpc := NewEtherscanAPI("", EtherscanURL(server.URL), EtherscanKeyRate(10))
+
+	// requests are sent without api key at the anonymous rate
+	n, err := rpc.EthBlockNumber()
+	require.Nil(t, err)
+	require.Equal(t, 16, n)
+	_, ok := (<-got)["apikey"]
+	require.False(t, ok)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = rpc.EthBlockNumberContext(ctx)
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.Len(t, rpc.KeyStatus(), 1)
+}
@@ -0,0 +1,277 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// addressPattern - hex address, the only input used as cache file name
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// ABIArgument - input or output of contract abi entry
+type ABIArgument struct {
+	Name         string        `json:"name"`
+	Type         string        `json:"type"`
+	InternalType string        `json:"internalType,omitempty"`
+	Indexed      bool          `json:"indexed,omitempty"`
+	Components   []ABIArgument `json:"components,omitempty"`
+}
+
+// ABIEntry - function, event, error, constructor, fallback or receive of contract abi
+type ABIEntry struct {
+	Type            string        `json:"type"`
+	Name            string        `json:"name,omitempty"`
+	Inputs          []ABIArgument `json:"inputs,omitempty"`
+	Outputs         []ABIArgument `json:"outputs,omitempty"`
+	StateMutability string        `json:"stateMutability,omitempty"`
+	Anonymous       bool          `json:"anonymous,omitempty"`
+}
+
+// ContractSource - verified source code of contract
+type ContractSource struct {
+	ContractName     string
+	SourceCode       string
+	ABI              []ABIEntry
+	CompilerVersion  string
+	OptimizationUsed bool
+	Runs             int
+	ConstructorArgs  string
+	EVMVersion       string
+	Library          string
+	LicenseType      string
+	// Proxy is set for proxy contracts, Implementation is the address of implementation contract
+	Proxy          bool
+	Implementation string
+	SwarmSource    string
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *ContractSource) UnmarshalJSON(data []byte) error {
+	proxy := new(proxyContractSource)
+	if err := json.Unmarshal(data, proxy); err != nil {
+		return err
+	}
+
+	abi, err := parseABI(proxy.ABI)
+	if err != nil {
+		return err
+	}
+	*s = ContractSource{
+		ContractName:     proxy.ContractName,
+		SourceCode:       proxy.SourceCode,
+		ABI:              abi,
+		CompilerVersion:  proxy.CompilerVersion,
+		OptimizationUsed: proxy.OptimizationUsed == "1",
+		Runs:             int(proxy.Runs),
+		ConstructorArgs:  proxy.ConstructorArguments,
+		EVMVersion:       proxy.EVMVersion,
+		Library:          proxy.Library,
+		LicenseType:      proxy.LicenseType,
+		Proxy:            proxy.Proxy == "1",
+		Implementation:   proxy.Implementation,
+		SwarmSource:      proxy.SwarmSource,
+	}
+
+	return nil
+}
+
+// ContractCreation - creator and creation transaction of contract
+type ContractCreation struct {
+	ContractAddress string
+	ContractCreator string
+	TxHash          string
+	BlockNumber     int
+	Timestamp       int
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *ContractCreation) UnmarshalJSON(data []byte) error {
+	proxy := new(proxyContractCreation)
+	if err := json.Unmarshal(data, proxy); err != nil {
+		return err
+	}
+
+	*c = ContractCreation{
+		ContractAddress: proxy.ContractAddress,
+		ContractCreator: proxy.ContractCreator,
+		TxHash:          proxy.TxHash,
+		BlockNumber:     int(proxy.BlockNumber),
+		Timestamp:       int(proxy.Timestamp),
+	}
+
+	return nil
+}
+
+type proxyContractSource struct {
+	SourceCode           string `json:"SourceCode"`
+	ABI                  string `json:"ABI"`
+	ContractName         string `json:"ContractName"`
+	CompilerVersion      string `json:"CompilerVersion"`
+	OptimizationUsed     string `json:"OptimizationUsed"`
+	Runs                 decInt `json:"Runs"`
+	ConstructorArguments string `json:"ConstructorArguments"`
+	EVMVersion           string `json:"EVMVersion"`
+	Library              string `json:"Library"`
+	LicenseType          string `json:"LicenseType"`
+	Proxy                string `json:"Proxy"`
+	Implementation       string `json:"Implementation"`
+	SwarmSource          string `json:"SwarmSource"`
+}
+
+type proxyContractCreation struct {
+	ContractAddress string `json:"contractAddress"`
+	ContractCreator string `json:"contractCreator"`
+	TxHash          string `json:"txHash"`
+	BlockNumber     decInt `json:"blockNumber"`
+	Timestamp       decInt `json:"timestamp"`
+}
+
+// abiNotVerified - abi of getsourcecode for contract without verified source
+const abiNotVerified = "Contract source code not verified"
+
+// parseABI parses abi json string, abi of unverified contract is empty
+func parseABI(abi string) ([]ABIEntry, error) {
+	if abi == "" || abi == abiNotVerified {
+		return nil, nil
+	}
+
+	var entries []ABIEntry
+	if err := json.Unmarshal([]byte(abi), &entries); err != nil {
+		return nil, fmt.Errorf("invalid abi: %v", err)
+	}
+	return entries, nil
+}
+
+// EtherscanCache set directory caching contract abi and source code,
+// verified contracts are fetched once
+func EtherscanCache(dir string) func(x *EtherscanAPI) {
+	return func(x *EtherscanAPI) {
+		x.cacheDir = dir
+	}
+}
+
+// cached returns result of contract action from cache, fetched result is cached.
+// Address which is not a hex address bypasses the cache, it must not name a file outside of cache dir
+func (x *EtherscanAPI) cached(action, address string, fetch func() (json.RawMessage, error)) (json.RawMessage, error) {
+	if x.cacheDir == "" || !addressPattern.MatchString(address) {
+		return fetch()
+	}
+
+	host := "etherscan"
+	if u, err := url.Parse(x.url); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	path := filepath.Join(x.cacheDir, host, action, strings.ToLower(address)+".json")
+	if data, err := ioutil.ReadFile(path); err == nil {
+		return data, nil
+	}
+
+	result, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	// cache failures are not fatal, result is fetched again next time
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+		tmp := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+		if err := ioutil.WriteFile(tmp, result, 0644); err == nil {
+			os.Rename(tmp, path)
+		}
+	}
+	return result, nil
+}
+
+// ContractABI returns abi of verified contract, see ContractABIContext
+func (x *EtherscanAPI) ContractABI(address string) ([]ABIEntry, error) {
+	return x.ContractABIContext(context.Background(), address)
+}
+
+// ContractABIContext returns abi of verified contract, ErrNotFound for unverified contract.
+func (x *EtherscanAPI) ContractABIContext(ctx context.Context, address string) ([]ABIEntry, error) {
+	result, err := x.cached("getabi", address, func() (json.RawMessage, error) {
+		return x.query(ctx, "contract", "getabi", url.Values{"address": {address}})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var abi string
+	if err := unmarshalResult(result, &abi); err != nil {
+		return nil, err
+	}
+	return parseABI(abi)
+}
+
+// ContractSource returns verified source code of contract, see ContractSourceContext
+func (x *EtherscanAPI) ContractSource(address string) (*ContractSource, error) {
+	return x.ContractSourceContext(context.Background(), address)
+}
+
+// ContractSourceContext returns verified source code of contract, ErrNotFound for unverified contract.
+func (x *EtherscanAPI) ContractSourceContext(ctx context.Context, address string) (*ContractSource, error) {
+	result, err := x.cached("getsourcecode", address, func() (json.RawMessage, error) {
+		result, err := x.query(ctx, "contract", "getsourcecode", url.Values{"address": {address}})
+		if err != nil {
+			return nil, err
+		}
+
+		// unverified contract is not cached, it may be verified later
+		var sources []proxyContractSource
+		if err := unmarshalResult(result, &sources); err != nil {
+			return nil, err
+		}
+		if len(sources) == 0 || sources[0].ABI == abiNotVerified {
+			return nil, fmt.Errorf("contract %s source code %w", address, ErrNotFound)
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []ContractSource
+	if err := unmarshalResult(result, &sources); err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("contract %s source code %w", address, ErrNotFound)
+	}
+	return &sources[0], nil
+}
+
+// ContractCreation returns creator and creation transaction of contracts, see ContractCreationContext
+func (x *EtherscanAPI) ContractCreation(addresses ...string) ([]ContractCreation, error) {
+	return x.ContractCreationContext(context.Background(), addresses...)
+}
+
+// ContractCreationContext returns creator and creation transaction of contracts,
+// etherscan takes up to 5 addresses per request.
+func (x *EtherscanAPI) ContractCreationContext(ctx context.Context, addresses ...string) ([]ContractCreation, error) {
+	var creations []ContractCreation
+	for start := 0; start < len(addresses); start += 5 {
+		end := start + 5
+		if end > len(addresses) {
+			end = len(addresses)
+		}
+
+		query := url.Values{"contractaddresses": {strings.Join(addresses[start:end], ",")}}
+		result, err := x.query(ctx, "contract", "getcontractcreation", query)
+		if err != nil {
+			return nil, err
+		}
+
+		var chunk []ContractCreation
+		if err := unmarshalResult(result, &chunk); err != nil {
+			return nil, err
+		}
+		creations = append(creations, chunk...)
+	}
+	return creations, nil
+}
@@ -0,0 +1,78 @@
+package ethrpc
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEtherscanContractABICache(t *testing.T) {
+	got := make(chan url.Values, 10)
+	server := newEtherscanServer(map[string]string{
+		"contract/getabi": `{"status":"1","message":"OK","result":"[{\"constant\":true,\"inputs\":[{\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"}]"}`,
+	}, got)
+	defer server.Close()
+
+	dir := t.TempDir()
+	rpc := NewEtherscanAPI("key", EtherscanURL(server.URL), EtherscanCache(dir))
+
+	for i := 0; i < 2; i++ {
+		abi, err := rpc.ContractABI("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+		require.Nil(t, err)
+		require.Len(t, abi, 2)
+		require.Equal(t, "balanceOf", abi[0].Name)
+		require.Equal(t, "view", abi[0].StateMutability)
+		require.Equal(t, "event", abi[1].Type)
+		require.True(t, abi[1].Inputs[0].Indexed)
+	}
+	require.Len(t, got, 1)
+
+	// cache is shared by clients
+	rpc = NewEtherscanAPI("key", EtherscanURL(server.URL), EtherscanCache(dir))
+	_, err := rpc.ContractABI("0xdac17f958d2ee523a2206206994597c13d831ec7")
+	require.Nil(t, err)
+	require.Len(t, got, 1)
+
+	// address which is not a hex address is not cached
+	for i := 0; i < 2; i++ {
+		_, err = rpc.ContractABI("../../../abi")
+		require.Nil(t, err)
+	}
+	require.Len(t, got, 3)
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*", "*"))
+	require.Nil(t, err)
+	require.Len(t, files, 1)
+	_, err = os.Stat(filepath.Join(dir, "..", "abi.json"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestEtherscanContractSource(t *testing.T) {
+	got := make(chan url.Values, 10)
+	server := newEtherscanServer(map[string]string{
+		"contract/getsourcecode":       `{"status":"1","message":"OK","result":[{"SourceCode":"","ABI":"Contract source code not verified","ContractName":"","CompilerVersion":"","OptimizationUsed":"","Runs":"","ConstructorArguments":"","EVMVersion":"Default","Library":"","LicenseType":"Unknown","Proxy":"0","Implementation":"","SwarmSource":""}]}`,
+		"contract/getabi":              `{"status":"0","message":"NOTOK","result":"Contract source code not verified"}`,
+		"contract/getcontractcreation": `{"status":"1","message":"OK","result":[{"contractAddress":"0xb83c27805aaca5c7082eb45c868d955cf04c337f","contractCreator":"0xa7c2fe5f5a6b3d3f4e0a5e1e3d6a9b3c5f2d8e1a","txHash":"0x5cc18bf2a8d5d5a5f7a1d0c9b8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9","blockNumber":"15493839","timestamp":"1662570342"}]}`,
+	}, got)
+	defer server.Close()
+
+	rpc := NewEtherscanAPI("key", EtherscanURL(server.URL), EtherscanCache(t.TempDir()))
+
+	// unverified contract is not cached
+	for i := 0; i < 2; i++ {
+		_, err := rpc.ContractSource("0x0")
+		require.True(t, errors.Is(err, ErrNotFound))
+	}
+	_, err := rpc.ContractABI("0x0")
+	require.True(t, errors.Is(err, ErrNotFound))
+	require.Len(t, got, 3)
+
+	creations, err := rpc.ContractCreation("0xb83c27805aaca5c7082eb45c868d955cf04c337f")
+	require.Nil(t, err)
+	require.Len(t, creations, 1)
+	require.Equal(t, 15493839, creations[0].BlockNumber)
+	require.Equal(t, "0xb83c27805aaca5c7082eb45c868d955cf04c337f", creations[0].ContractAddress)
+}
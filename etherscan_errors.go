@@ -0,0 +1,101 @@
+package ethrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrHistoryTruncated - account history exceeds the 10000 records etherscan serves of a query
+// and can not be continued by block range, records fetched so far are returned along with it
+var ErrHistoryTruncated = errors.New("history truncated at etherscan result window")
+
+// EtherscanError - request rejected by etherscan with status "0" envelope,
+// or proxy call answered with a message instead of result
+type EtherscanError struct {
+	Module  string
+	Action  string
+	Message string
+	// Result is the detailed reason, e.g. "Max rate limit reached" or "Invalid API Key"
+	Result string
+}
+
+func (err *EtherscanError) Error() string {
+	if err.Message == "" {
+		return fmt.Sprintf("etherscan %s/%s: %s", err.Module, err.Action, err.Result)
+	}
+	return fmt.Sprintf("etherscan %s/%s: %s %s", err.Module, err.Action, err.Message, err.Result)
+}
+
+// Is classifies exhausted api key as ErrRateLimited, unverified contract and missing records as ErrNotFound,
+// unknown module or action as ErrNotSupported
+func (err *EtherscanError) Is(target error) bool {
+	result := strings.ToLower(err.Result)
+	switch target {
+	case ErrRateLimited:
+		return strings.Contains(result, "rate limit reached")
+	case ErrNotFound:
+		return strings.Contains(result, "not verified") || strings.HasPrefix(strings.ToLower(err.Message), "no ") && strings.HasSuffix(err.Message, " found")
+	case ErrNotSupported:
+		return strings.Contains(result, "invalid module name") || strings.Contains(result, "invalid action name")
+	}
+	return false
+}
+
+// keyCooldown returns how long the api key of request is disabled, 0 when the key is not at fault
+func (err *EtherscanError) keyCooldown() time.Duration {
+	result := strings.ToLower(err.Result)
+	switch {
+	case strings.Contains(result, "daily rate limit reached"):
+		return time.Hour
+	case strings.Contains(result, "rate limit reached"):
+		return time.Second
+	case strings.Contains(result, "invalid api key"), strings.Contains(result, "missing/invalid api key"):
+		return 10 * time.Minute
+	}
+	return 0
+}
+
+// etherscanResponse - response envelope of etherscan, proxy module answers with json-rpc response
+// unless the request is rejected
+type etherscanResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+	Error   *EthError       `json:"error"`
+}
+
+// decodeEtherscan returns result of module action response, failures are returned as
+// *EthError of proxy module or *EtherscanError, empty record list is a result
+func decodeEtherscan(module, action string, data []byte) (json.RawMessage, error) {
+	resp := new(etherscanResponse)
+	if err := json.Unmarshal(data, resp); err != nil {
+		return nil, fmt.Errorf("etherscan %s/%s invalid response: %v", module, action, err)
+	}
+	if resp.Error != nil {
+		return nil, *resp.Error
+	}
+
+	var result string
+	isString := len(resp.Result) > 0 && resp.Result[0] == '"' && json.Unmarshal(resp.Result, &result) == nil
+	failure := &EtherscanError{Module: module, Action: action, Message: resp.Message, Result: result}
+
+	switch {
+	case resp.Status == "0":
+		// no records of a list action
+		if !isString && failure.Is(ErrNotFound) {
+			return resp.Result, nil
+		}
+		return nil, failure
+	case module == "proxy":
+		// proxy results are hex strings, objects or null
+		if isString && !strings.HasPrefix(result, "0x") {
+			return nil, failure
+		}
+	case resp.Status != "1":
+		return nil, failure
+	}
+	return resp.Result, nil
+}
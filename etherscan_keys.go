@@ -0,0 +1,170 @@
+package ethrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// anonymousRate - requests per second etherscan allows without api key
+const anonymousRate = 0.2
+
+// EtherscanKeyStatus - usage of api key
+type EtherscanKeyStatus struct {
+	// Key is the masked api key
+	Key      string
+	Requests int64
+	Failures int64
+	// DisabledUntil is set while the key is exhausted or invalid
+	DisabledUntil time.Time
+}
+
+type apiKey struct {
+	token   string
+	limiter *rateLimiter
+
+	// guarded by keyPool.mu
+	requests      int64
+	failures      int64
+	disabledUntil time.Time
+	disabledBy    error
+}
+
+// keyPool distributes requests over api keys round robin within per key rate,
+// keys rejected by etherscan are disabled for a while
+type keyPool struct {
+	mu   sync.Mutex
+	keys []*apiKey
+	next int
+}
+
+// newKeyPool creates pool of non empty tokens, without tokens requests are sent with
+// a single empty key at the anonymous rate of etherscan
+func newKeyPool(tokens []string, perSecond float64) *keyPool {
+	pool := &keyPool{}
+	for _, token := range tokens {
+		if token = strings.TrimSpace(token); token != "" {
+			pool.keys = append(pool.keys, &apiKey{token: token})
+		}
+	}
+	if len(pool.keys) == 0 {
+		pool.keys = append(pool.keys, &apiKey{})
+	}
+	pool.setRate(perSecond)
+	return pool
+}
+
+func (p *keyPool) setRate(perSecond float64) {
+	for _, key := range p.keys {
+		rate := perSecond
+		if key.token == "" && (rate <= 0 || rate > anonymousRate) {
+			rate = anonymousRate
+		}
+		key.limiter = newRateLimiter(RateLimit{PerSecond: rate})
+	}
+}
+
+// pick returns the next key which is available soonest and how long to wait for it
+func (p *keyPool) pick(now time.Time) (*apiKey, time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var best *apiKey
+	var bestDelay time.Duration
+	bestIndex := 0
+	for i := range p.keys {
+		index := (p.next + i) % len(p.keys)
+		key := p.keys[index]
+
+		delay := key.disabledUntil.Sub(now)
+		if limit := key.limiter.delay(1); limit > delay {
+			delay = limit
+		}
+		if best == nil || delay < bestDelay {
+			best, bestDelay, bestIndex = key, delay, index
+		}
+		if delay <= 0 {
+			break
+		}
+	}
+	p.next = bestIndex + 1
+	return best, bestDelay
+}
+
+// acquire returns key to send next request with, waiting for a key within rate.
+// It fails when every key is disabled for longer than a second
+func (p *keyPool) acquire(ctx context.Context) (*apiKey, error) {
+	for {
+		now := time.Now()
+		key, delay := p.pick(now)
+		p.mu.Lock()
+		until, cause := key.disabledUntil, key.disabledBy
+		p.mu.Unlock()
+		if until.Sub(now) > time.Second {
+			return nil, fmt.Errorf("etherscan api keys disabled until %s: %w", until.Format(time.RFC3339), cause)
+		}
+		if delay <= 0 {
+			if err := key.limiter.wait(ctx, 1); err != nil {
+				return nil, err
+			}
+			p.mu.Lock()
+			key.requests++
+			p.mu.Unlock()
+			return key, nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// release records result of request sent with key, key rejected by etherscan is disabled
+func (p *keyPool) release(key *apiKey, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err == nil || errors.As(err, new(EthError)) {
+		return
+	}
+	key.failures++
+
+	var etherscanErr *EtherscanError
+	var httpErr *HTTPError
+	switch {
+	case errors.As(err, &etherscanErr) && etherscanErr.keyCooldown() > 0:
+		key.disabledUntil, key.disabledBy = time.Now().Add(etherscanErr.keyCooldown()), err
+	case errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusForbidden:
+		key.disabledUntil, key.disabledBy = time.Now().Add(time.Second), err
+	}
+}
+
+func (p *keyPool) status() []EtherscanKeyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := make([]EtherscanKeyStatus, len(p.keys))
+	for i, key := range p.keys {
+		masked := key.token
+		if len(masked) > 4 {
+			masked = strings.Repeat("*", len(masked)-4) + masked[len(masked)-4:]
+		}
+		status[i] = EtherscanKeyStatus{
+			Key:      masked,
+			Requests: key.requests,
+			Failures: key.failures,
+		}
+		if time.Now().Before(key.disabledUntil) {
+			status[i].DisabledUntil = key.disabledUntil
+		}
+	}
+	return status
+}
@@ -1,21 +1,38 @@
 package ethrpc
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"sync/atomic"
 )
 
 // EthError - ethereum error
 type EthError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
 }
 
 func (err EthError) Error() string {
 	return fmt.Sprintf("EthError %d (%s)", err.Code, err.Message)
 }
 
+// Is classifies error codes as ErrNotSupported, ErrNotFound or ErrRateLimited,
+// and messages of rejected transaction as ErrNonceTooLow, ErrReplacementUnderpriced, ErrInsufficientFunds or ErrAlreadyKnown
+func (err EthError) Is(target error) bool {
+	switch target {
+	case ErrNotSupported:
+		return err.Code == CodeMethodNotFound || err.Code == CodeMethodNotSupported
+	case ErrNotFound:
+		return err.Code == CodeResourceNotFound
+	case ErrRateLimited:
+		return err.Code == CodeLimitExceeded
+	}
+	return isTxRejection(err.Message, target)
+}
+
 type EthResponse struct {
 	ID      int             `json:"id,omitempty"`
 	Version string          `json:"jsonrpc"`
@@ -30,9 +47,13 @@ type EthRequest struct {
 	Params  []interface{} `json:"params"`
 }
 
+// requestID is the last id given by NewEthRequest
+var requestID int64
+
+// NewEthRequest create request with process wide unique id
 func NewEthRequest(method string, params ...interface{}) EthRequest {
 	return EthRequest{
-		ID:      1,
+		ID:      int(atomic.AddInt64(&requestID, 1)),
 		Version: "2.0",
 		Method:  method,
 		Params:  params,
@@ -46,6 +67,8 @@ var (
 )
 
 type EthRPC interface {
+	EthRPCContext
+
 	String() string
 	Debug(bool)
 	BatchCall(reqs []EthRequest) ([]EthResponse, error)
@@ -58,14 +81,59 @@ type EthRPC interface {
 	EthSyncing() (*Syncing, error)
 	EthGasPrice() (big.Int, error)
 	EthBlockNumber() (int, error)
-	EthGetBalance(address, block string) (big.Int, error)
-	EthGetStorageAt(data string, position int, tag string) (string, error)
-	EthGetTransactionCount(address, block string) (int, error)
-	EthGetBlockTransactionCountByNumber(number int) (int, error)
-	EthGetBlockByNumber(number int, withTransactions bool) (*Block, error)
+	EthGetBalance(address string, block BlockNumberOrTag) (big.Int, error)
+	EthGetStorageAt(data string, position int, tag BlockNumberOrTag) (string, error)
+	EthGetTransactionCount(address string, block BlockNumberOrTag) (int, error)
+	EthCall(msg CallMsg, block BlockNumberOrTag) (string, error)
+	EthEstimateGas(msg CallMsg) (int, error)
+	EthGetCode(address string, block BlockNumberOrTag) (string, error)
+	EthSendRawTransaction(data string) (string, error)
+	EthGetBlockTransactionCountByNumber(number BlockNumberOrTag) (int, error)
+	EthGetBlockTransactionCountByHash(hash string) (int, error)
+	EthGetBlockByNumber(number BlockNumberOrTag, withTransactions bool) (*Block, error)
+	EthGetBlockByHash(hash string, withTransactions bool) (*Block, error)
 	EthGetTransactionByHash(hash string) (*Transaction, error)
-	EthGetTransactionByBlockNumberAndIndex(blockNumber, transactionIndex int) (*Transaction, error)
+	EthGetTransactionByBlockNumberAndIndex(blockNumber BlockNumberOrTag, transactionIndex int) (*Transaction, error)
+	EthGetTransactionByBlockHashAndIndex(blockHash string, transactionIndex int) (*Transaction, error)
 	EthGetTransactionReceipt(hash string) (*TransactionReceipt, error)
 	EthGetLogs(params FilterParams) ([]Log, error)
-	EthGetUncleByBlockNumberAndIndex(number int, pos int) (*Block, error)
-}
\ No newline at end of file
+	EthGetUncleByBlockNumberAndIndex(number BlockNumberOrTag, pos int) (*Block, error)
+	EthGetUncleCountByBlockNumber(number BlockNumberOrTag) (int, error)
+	EthGetUncleCountByBlockHash(hash string) (int, error)
+	EthGetUncleByBlockHashAndIndex(hash string, pos int) (*Block, error)
+}
+
+// EthRPCContext is the context aware variant of EthRPC,
+// ctx cancels the in-flight request or bounds it with a deadline.
+type EthRPCContext interface {
+	BatchCallContext(ctx context.Context, reqs []EthRequest) ([]EthResponse, error)
+	CallContext(ctx context.Context, method string, params ...interface{}) (json.RawMessage, error)
+	Web3ClientVersionContext(ctx context.Context) (string, error)
+	NetVersionContext(ctx context.Context) (string, error)
+	NetListeningContext(ctx context.Context) (bool, error)
+	NetPeerCountContext(ctx context.Context) (int, error)
+	EthProtocolVersionContext(ctx context.Context) (string, error)
+	EthSyncingContext(ctx context.Context) (*Syncing, error)
+	EthGasPriceContext(ctx context.Context) (big.Int, error)
+	EthBlockNumberContext(ctx context.Context) (int, error)
+	EthGetBalanceContext(ctx context.Context, address string, block BlockNumberOrTag) (big.Int, error)
+	EthGetStorageAtContext(ctx context.Context, data string, position int, tag BlockNumberOrTag) (string, error)
+	EthGetTransactionCountContext(ctx context.Context, address string, block BlockNumberOrTag) (int, error)
+	EthCallContext(ctx context.Context, msg CallMsg, block BlockNumberOrTag) (string, error)
+	EthEstimateGasContext(ctx context.Context, msg CallMsg) (int, error)
+	EthGetCodeContext(ctx context.Context, address string, block BlockNumberOrTag) (string, error)
+	EthSendRawTransactionContext(ctx context.Context, data string) (string, error)
+	EthGetBlockTransactionCountByNumberContext(ctx context.Context, number BlockNumberOrTag) (int, error)
+	EthGetBlockTransactionCountByHashContext(ctx context.Context, hash string) (int, error)
+	EthGetBlockByNumberContext(ctx context.Context, number BlockNumberOrTag, withTransactions bool) (*Block, error)
+	EthGetBlockByHashContext(ctx context.Context, hash string, withTransactions bool) (*Block, error)
+	EthGetTransactionByHashContext(ctx context.Context, hash string) (*Transaction, error)
+	EthGetTransactionByBlockNumberAndIndexContext(ctx context.Context, blockNumber BlockNumberOrTag, transactionIndex int) (*Transaction, error)
+	EthGetTransactionByBlockHashAndIndexContext(ctx context.Context, blockHash string, transactionIndex int) (*Transaction, error)
+	EthGetTransactionReceiptContext(ctx context.Context, hash string) (*TransactionReceipt, error)
+	EthGetLogsContext(ctx context.Context, params FilterParams) ([]Log, error)
+	EthGetUncleByBlockNumberAndIndexContext(ctx context.Context, number BlockNumberOrTag, pos int) (*Block, error)
+	EthGetUncleCountByBlockNumberContext(ctx context.Context, number BlockNumberOrTag) (int, error)
+	EthGetUncleCountByBlockHashContext(ctx context.Context, hash string) (int, error)
+	EthGetUncleByBlockHashAndIndexContext(ctx context.Context, hash string, pos int) (*Block, error)
+}
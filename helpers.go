@@ -38,3 +38,33 @@ func BigToHex(bigInt big.Int) string {
 
 	return "0x" + strings.TrimPrefix(fmt.Sprintf("%x", bigInt.Bytes()), "0")
 }
+
+// EIP-4844 blob gas pricing, Prague raised the update fraction with EIP-7691
+const (
+	MinBlobBaseFee                  = 1
+	BlobBaseFeeUpdateFractionCancun = 3338477
+	BlobBaseFeeUpdateFractionPrague = 5007716
+)
+
+// BlobBaseFee returns the price per blob gas in wei of block with excessBlobGas,
+// updateFraction is the one of block fork, e.g. BlobBaseFeeUpdateFractionCancun, it returns nil
+// for non positive updateFraction
+func BlobBaseFee(excessBlobGas, updateFraction int) *big.Int {
+	if updateFraction <= 0 {
+		return nil
+	}
+	return fakeExponential(big.NewInt(MinBlobBaseFee), big.NewInt(int64(excessBlobGas)), big.NewInt(int64(updateFraction)))
+}
+
+// fakeExponential approximates factor * e ** (numerator / denominator) with Taylor expansion as specified by EIP-4844
+func fakeExponential(factor, numerator, denominator *big.Int) *big.Int {
+	output := new(big.Int)
+	accum := new(big.Int).Mul(factor, denominator)
+	for i := int64(1); accum.Sign() > 0; i++ {
+		output.Add(output, accum)
+		accum.Mul(accum, numerator)
+		accum.Div(accum, new(big.Int).Mul(denominator, big.NewInt(i)))
+	}
+
+	return output.Div(output, denominator)
+}
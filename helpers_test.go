@@ -49,3 +49,32 @@ func TestBigToHex(t *testing.T) {
 	i3, _ := big.NewInt(0).SetString("0", 10)
 	assert.Equal(t, "0x0", BigToHex(*i3))
 }
+
+func TestFakeExponential(t *testing.T) {
+	for _, c := range []struct {
+		factor, numerator, denominator int64
+		want                           int64
+	}{
+		{1, 0, 1, 1},
+		{38493, 0, 1000, 38493},
+		{0, 1234, 2345, 0},
+		{1, 2, 1, 6},
+		{1, 4, 2, 6},
+		{1, 3, 1, 16},
+		{10, 8, 2, 542},
+		{2, 5, 2, 23},
+		{1, 50000000, 2225652, 5709098764},
+	} {
+		got := fakeExponential(big.NewInt(c.factor), big.NewInt(c.numerator), big.NewInt(c.denominator))
+		assert.Equal(t, c.want, got.Int64(), "%+v", c)
+	}
+}
+
+func TestBlobBaseFee(t *testing.T) {
+	assert.Equal(t, "1", BlobBaseFee(0, BlobBaseFeeUpdateFractionCancun).String())
+	assert.Equal(t, "1", BlobBaseFee(2314057, BlobBaseFeeUpdateFractionCancun).String())
+	assert.Equal(t, "2", BlobBaseFee(2314058, BlobBaseFeeUpdateFractionCancun).String())
+	assert.Equal(t, "1", BlobBaseFee(2314058, BlobBaseFeeUpdateFractionPrague).String())
+	assert.Nil(t, BlobBaseFee(2314058, 0))
+	assert.Nil(t, BlobBaseFee(2314058, -1))
+}
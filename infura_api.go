@@ -1,341 +1,83 @@
 package ethrpc
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"math/big"
 	"net/http"
 )
 
 type InfuraAPI struct {
-	url string
+	rpcClient
+
+	url    string
 	client *http.Client
-	debug  bool
+	auth   HTTPAuth
 }
 
-func InfuraHost(url string) func (x *InfuraAPI) {
+func InfuraHost(url string) func(x *InfuraAPI) {
 	return func(x *InfuraAPI) {
 		x.url = url
 	}
 }
 
-// New create new rpc client with given url
-func NewInfuraAPI(options ...func(x *InfuraAPI)) *InfuraAPI {
-	rpc := &InfuraAPI{
-		url:    "https://mainnet.infura.io/",
-		client: http.DefaultClient,
-	}
-	for _, option := range options {
-		option(rpc)
-	}
-
-	return rpc
-}
-
-func (x *InfuraAPI) String() string {
-	return "infura"
-}
-
-func (x *InfuraAPI) Debug(debug bool) {
-	x.debug = debug
-}
-
-func (x *InfuraAPI) call(method string, target interface{}, params ...interface{}) error {
-	result, err := x.Call(method, params...)
-	if err != nil {
-		return err
-	}
-
-	if target == nil {
-		return nil
-	}
-
-	if bytes.Equal(result, []byte("null")) {
-		return fmt.Errorf("result null")
-	}
-	return json.Unmarshal(result, target)
-}
-
-//batch request
-func (x *InfuraAPI) BatchCall(reqs []EthRequest) ([]EthResponse, error) {
-	body, err := json.Marshal(reqs)
-	if err != nil {
-		return nil, err
-	}
-
-	response, err := x.client.Post(x.url, "application/json", bytes.NewBuffer(body))
-	if response != nil {
-		defer response.Body.Close()
-	}
-	if err != nil {
-		return nil, err
-	}
-	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("http status code error %d", response.StatusCode)
-	}
-
-	data, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	if x.debug {
-		fmt.Printf("requests %s responses %s\n", body, data)
-		fmt.Printf("requests size %d responses size %d\n", len(body), len(data))
-	}
-
-	resps := &[]EthResponse{}
-	if err := json.Unmarshal(data, resps); err != nil {
-		return nil, err
-	}
-	return *resps, nil
-}
-
-// Call returns raw response of method call
-func (x *InfuraAPI) Call(method string, params ...interface{}) (json.RawMessage, error) {
-	request := EthRequest{
-		ID:      1,
-		Version: "2.0",
-		Method:  method,
-		Params:  params,
-	}
-
-	body, err := json.Marshal(request)
-	if err != nil {
-		return nil, err
-	}
-
-	response, err := x.client.Post(x.url, "application/json", bytes.NewBuffer(body))
-	if response != nil {
-		defer response.Body.Close()
-	}
-	if err != nil {
-		return nil, err
-	}
-	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("http status code error %d", response.StatusCode)
-	}
-
-	data, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	if x.debug {
-		fmt.Printf("request %s %s response %s\n", method, body, data)
-	}
-
-	resp := new(EthResponse)
-	if err := json.Unmarshal(data, resp); err != nil {
-		return nil, err
-	}
-
-	if resp.Error != nil {
-		return nil, *resp.Error
-	}
-
-	return resp.Result, nil
-}
-
-// Web3ClientVersion returns the current client version.
-func (x *InfuraAPI) Web3ClientVersion() (string, error) {
-	var clientVersion string
-
-	err := x.call("web3_clientVersion", &clientVersion)
-	return clientVersion, err
-}
-
-// NetVersion returns the current network protocol version.
-func (x *InfuraAPI) NetVersion() (string, error) {
-	var version string
-
-	err := x.call("net_version", &version)
-	return version, err
-}
-
-// NetListening returns true if client is actively listening for network connections.
-func (x *InfuraAPI) NetListening() (bool, error) {
-	var listening bool
-
-	err := x.call("net_listening", &listening)
-	return listening, err
-}
-
-// NetPeerCount returns number of peers currently connected to the client.
-func (x *InfuraAPI) NetPeerCount() (int, error) {
-	var response string
-	if err := x.call("net_peerCount", &response); err != nil {
-		return 0, err
-	}
-
-	return ParseInt(response)
-}
-
-// EthProtocolVersion returns the current ethereum protocol version.
-func (x *InfuraAPI) EthProtocolVersion() (string, error) {
-	var protocolVersion string
-
-	err := x.call("eth_protocolVersion", &protocolVersion)
-	return protocolVersion, err
-}
-
-// EthSyncing returns an object with data about the sync status or false.
-func (x *InfuraAPI) EthSyncing() (*Syncing, error) {
-	result, err := x.Call("eth_syncing")
-	if err != nil {
-		return nil, err
-	}
-	syncing := new(Syncing)
-	if bytes.Equal(result, []byte("false")) {
-		return syncing, nil
-	}
-	err = json.Unmarshal(result, syncing)
-	return syncing, err
-}
-
-// EthGasPrice returns the current price per gas in wei.
-func (x *InfuraAPI) EthGasPrice() (big.Int, error) {
-	var response string
-	if err := x.call("eth_gasPrice", &response); err != nil {
-		return big.Int{}, err
-	}
-
-	return ParseBigInt(response)
-}
-
-// EthBlockNumber returns the number of most recent block.
-func (x *InfuraAPI) EthBlockNumber() (int, error) {
-	var response string
-	if err := x.call("eth_blockNumber", &response); err != nil {
-		return 0, err
+// InfuraProjectSecret set project secret for projects requiring it
+func InfuraProjectSecret(secret string) func(x *InfuraAPI) {
+	return func(x *InfuraAPI) {
+		x.auth = BasicAuth("", secret)
 	}
-
-	return ParseInt(response)
 }
 
-// EthGetBalance returns the balance of the account of given address in wei.
-func (x *InfuraAPI) EthGetBalance(address, block string) (big.Int, error) {
-	var response string
-	if err := x.call("eth_getBalance", &response, address, block); err != nil {
-		return big.Int{}, err
+// InfuraHTTPClient set http client used to reach infura
+func InfuraHTTPClient(client *http.Client) func(x *InfuraAPI) {
+	return func(x *InfuraAPI) {
+		x.client = client
 	}
-
-	return ParseBigInt(response)
 }
 
-// EthGetStorageAt returns the value from a storage position at a given address.
-func (x *InfuraAPI) EthGetStorageAt(data string, position int, tag string) (string, error) {
-	var result string
-
-	err := x.call("eth_getStorageAt", &result, data, IntToHex(position), tag)
-	return result, err
-}
-
-// EthGetTransactionCount returns the number of transactions sent from an address.
-func (x *InfuraAPI) EthGetTransactionCount(address, block string) (int, error) {
-	var response string
-
-	if err := x.call("eth_getTransactionCount", &response, address, block); err != nil {
-		return 0, err
+// InfuraBatchLimits set limits splitting large batches into concurrent requests
+func InfuraBatchLimits(limits BatchLimits) func(x *InfuraAPI) {
+	return func(x *InfuraAPI) {
+		x.batchLimits = limits
 	}
-
-	return ParseInt(response)
 }
 
-// EthGetBlockTransactionCountByNumber returns the number of transactions in a block from a block matching the given block
-func (x *InfuraAPI) EthGetBlockTransactionCountByNumber(number int) (int, error) {
-	var response string
-
-	if err := x.call("eth_getBlockTransactionCountByNumber", &response, IntToHex(number)); err != nil {
-		return 0, err
+// InfuraAutoBatch enable coalescing of concurrent calls into batches
+func InfuraAutoBatch(config AutoBatch) func(x *InfuraAPI) {
+	return func(x *InfuraAPI) {
+		x.batcher = newAutoBatcher(&x.rpcClient, config)
 	}
-
-	return ParseInt(response)
 }
 
-func (x *InfuraAPI) getBlock(method string, withTransactions bool, params ...interface{}) (*Block, error) {
-	var response ProxyBlock
-	if withTransactions {
-		response = new(ProxyBlockWithTransactions)
-	} else {
-		response = new(ProxyBlockWithoutTransactions)
-	}
-
-	err := x.call(method, response, params...)
-	if err != nil {
-		return nil, err
-	}
-	block := response.ToBlock()
-	if len(block.Hash) == 0 {
-		return nil, fmt.Errorf("block not found")
+// InfuraRetry set retry policy of transient failures
+func InfuraRetry(policy RetryPolicy) func(x *InfuraAPI) {
+	return func(x *InfuraAPI) {
+		x.retry = policy
 	}
-
-	return &block, nil
-}
-
-// EthGetBlockByNumber returns information about a block by block number.
-func (x *InfuraAPI) EthGetBlockByNumber(number int, withTransactions bool) (*Block, error) {
-	return x.getBlock("eth_getBlockByNumber", withTransactions, IntToHex(number), withTransactions)
 }
 
-func (x *InfuraAPI) EthGetUncleByBlockNumberAndIndex(number int, pos int) (*Block, error) {
-	uncleBlock := new(UncleBlock)
-
-	err := x.call("eth_getUncleByBlockNumberAndIndex", uncleBlock, IntToHex(number), IntToHex(pos))
-	if err != nil {
-		return nil, err
-	}
-
-	block := uncleBlock.ToBlock()
-	if len(block.Hash) == 0 {
-		return nil, fmt.Errorf("block not found")
+// InfuraRateLimit set client side rate limit of requests
+func InfuraRateLimit(limit RateLimit) func(x *InfuraAPI) {
+	return func(x *InfuraAPI) {
+		x.limiter = newRateLimiter(limit)
 	}
-
-	return &block, nil
 }
 
-func (x *InfuraAPI) getTransaction(method string, params ...interface{}) (*Transaction, error) {
-	transaction := new(Transaction)
-
-	err := x.call(method, transaction, params...)
-	if len(transaction.Hash) == 0 {
-		return nil, fmt.Errorf("tx not found")
+// New create new rpc client with given url
+func NewInfuraAPI(options ...func(x *InfuraAPI)) *InfuraAPI {
+	rpc := &InfuraAPI{
+		url:    "https://mainnet.infura.io/",
+		client: http.DefaultClient,
 	}
-	return transaction, err
-}
-
-// EthGetTransactionByHash returns the information about a transaction requested by transaction hash.
-func (x *InfuraAPI) EthGetTransactionByHash(hash string) (*Transaction, error) {
-	return x.getTransaction("eth_getTransactionByHash", hash)
-}
-
-// EthGetTransactionByBlockNumberAndIndex returns information about a transaction by block number and transaction index position.
-func (x *InfuraAPI) EthGetTransactionByBlockNumberAndIndex(blockNumber, transactionIndex int) (*Transaction, error) {
-	return x.getTransaction("eth_getTransactionByBlockNumberAndIndex", IntToHex(blockNumber), IntToHex(transactionIndex))
-}
-
-// EthGetTransactionReceipt returns the receipt of a transaction by transaction hash.
-// Note That the receipt is not available for pending transactions.
-func (x *InfuraAPI) EthGetTransactionReceipt(hash string) (*TransactionReceipt, error) {
-	transactionReceipt := new(TransactionReceipt)
-
-	err := x.call("eth_getTransactionReceipt", transactionReceipt, hash)
-	if err != nil {
-		return nil, err
+	for _, option := range options {
+		option(rpc)
 	}
 
-	if len(transactionReceipt.TransactionHash) == 0 {
-		return nil, fmt.Errorf("receipt not found")
+	if rpc.transport == nil {
+		rpc.transport = newTransport(rpc.url, rpc.client, rpc.auth)
 	}
+	rpc.init()
 
-	return transactionReceipt, nil
+	return rpc
 }
 
-// EthGetLogs returns an array of all logs matching a given filter object.
-func (x *InfuraAPI) EthGetLogs(params FilterParams) ([]Log, error) {
-	var logs []Log
-	err := x.call("eth_getLogs", &logs, params)
-	return logs, err
+func (x *InfuraAPI) String() string {
+	return "infura"
 }
@@ -0,0 +1,61 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+)
+
+// IPCTransport sends json-rpc requests over geth unix domain socket and supports subscriptions
+type IPCTransport struct {
+	streamTransport
+
+	path   string
+	dialer *net.Dialer
+}
+
+// NewIPCTransport create new ipc transport with given socket path, e.g. /data/geth/geth.ipc,
+// connection is opened on first request
+func NewIPCTransport(path string) *IPCTransport {
+	transport := &IPCTransport{
+		path:   strings.TrimPrefix(path, "unix://"),
+		dialer: &net.Dialer{},
+	}
+	transport.streamTransport = newStreamTransport("ipc-"+transport.path, transport.dialContext)
+
+	return transport
+}
+
+func (x *IPCTransport) dialContext(ctx context.Context) (streamConn, error) {
+	conn, err := x.dialer.DialContext(ctx, "unix", x.path)
+	if err != nil {
+		return nil, err
+	}
+	return &ipcConn{conn: conn, decoder: json.NewDecoder(conn)}, nil
+}
+
+// ipcConn reads the stream of json values written by node
+type ipcConn struct {
+	conn    net.Conn
+	decoder *json.Decoder
+}
+
+func (c *ipcConn) Write(ctx context.Context, data []byte) error {
+	deadline, _ := ctx.Deadline()
+	if err := c.conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	_, err := c.conn.Write(append(data, '\n'))
+	return err
+}
+
+func (c *ipcConn) Read() ([]byte, error) {
+	var msg json.RawMessage
+	err := c.decoder.Decode(&msg)
+	return msg, err
+}
+
+func (c *ipcConn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,97 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// serveFakeIPC answers every request with block number 0x10,
+// eth_subscribe is followed by a logs notification
+func serveFakeIPC(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func(conn net.Conn) {
+			defer conn.Close()
+			decoder := json.NewDecoder(conn)
+			encoder := json.NewEncoder(conn)
+			for {
+				var msg json.RawMessage
+				if err := decoder.Decode(&msg); err != nil {
+					return
+				}
+
+				if msg[0] == '[' {
+					var reqs []EthRequest
+					json.Unmarshal(msg, &reqs)
+					resps := make([]EthResponse, len(reqs))
+					for i := range reqs {
+						resps[i] = EthResponse{ID: reqs[i].ID, Version: "2.0", Result: json.RawMessage(`"0x10"`)}
+					}
+					encoder.Encode(resps)
+					continue
+				}
+
+				var req EthRequest
+				json.Unmarshal(msg, &req)
+				if req.Method == "eth_subscribe" {
+					encoder.Encode(EthResponse{ID: req.ID, Version: "2.0", Result: json.RawMessage(`"0x1"`)})
+					conn.Write([]byte(`{"jsonrpc":"2.0","method":"eth_subscription","params":{"subscription":"0x1","result":{"address":"0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf","blockNumber":"0x7f2cd","logIndex":"0x6"}}}`))
+					continue
+				}
+				encoder.Encode(EthResponse{ID: req.ID, Version: "2.0", Result: json.RawMessage(`"0x10"`)})
+			}
+		}(conn)
+	}
+}
+
+func TestIPCTransport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethrpc")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "geth.ipc")
+	listener, err := net.Listen("unix", path)
+	require.Nil(t, err)
+	defer listener.Close()
+	go serveFakeIPC(listener)
+
+	rpc := NewNodeAPI(path)
+	defer rpc.Close()
+
+	number, err := rpc.EthBlockNumber()
+	require.Nil(t, err)
+	require.Equal(t, 16, number)
+
+	resps, err := rpc.BatchCall([]EthRequest{
+		NewEthRequest("eth_blockNumber"),
+		NewEthRequest("eth_gasPrice"),
+	})
+	require.Nil(t, err)
+	require.Len(t, resps, 2)
+	require.Equal(t, `"0x10"`, string(resps[1].Result))
+
+	logs := make(chan Log, 1)
+	sub, err := rpc.SubscribeLogs(context.Background(), FilterParams{Address: []string{"0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf"}}, logs)
+	require.Nil(t, err)
+	defer sub.Unsubscribe()
+
+	select {
+	case log := <-logs:
+		require.Equal(t, 520909, log.BlockNumber)
+		require.Equal(t, 6, log.LogIndex)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting log")
+	}
+}
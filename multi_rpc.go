@@ -0,0 +1,476 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MultiRPC tries backends in priority order and fails over to the next one
+// on transport errors, http status errors and unsupported methods,
+// failing backends are benched for an exponentially growing cooldown
+type MultiRPC struct {
+	backgroundRPC
+
+	backends    []*multiBackend
+	minCooldown time.Duration
+	maxCooldown time.Duration
+}
+
+type multiBackend struct {
+	rpc EthRPC
+
+	mu           sync.Mutex
+	failures     int
+	benchedUntil time.Time
+}
+
+// MultiRPCCooldown set bench cooldown of failing backend, doubled on every consecutive failure
+func MultiRPCCooldown(min, max time.Duration) func(x *MultiRPC) {
+	return func(x *MultiRPC) {
+		x.minCooldown = min
+		x.maxCooldown = max
+	}
+}
+
+// NewMultiRPC create new rpc client over backends, first backend has the highest priority
+func NewMultiRPC(backends []EthRPC, options ...func(x *MultiRPC)) *MultiRPC {
+	rpc := &MultiRPC{
+		minCooldown: 5 * time.Second,
+		maxCooldown: 5 * time.Minute,
+	}
+	for _, backend := range backends {
+		rpc.backends = append(rpc.backends, &multiBackend{rpc: backend})
+	}
+	rpc.backgroundRPC = backgroundRPC{rpc}
+	for _, option := range options {
+		option(rpc)
+	}
+
+	return rpc
+}
+
+func (x *MultiRPC) String() string {
+	names := make([]string, len(x.backends))
+	for i, backend := range x.backends {
+		names[i] = backend.rpc.String()
+	}
+	return "multi(" + strings.Join(names, ",") + ")"
+}
+
+func (x *MultiRPC) Debug(debug bool) {
+	for _, backend := range x.backends {
+		backend.rpc.Debug(debug)
+	}
+}
+
+func (b *multiBackend) benched(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return now.Before(b.benchedUntil)
+}
+
+func (b *multiBackend) succeed() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.benchedUntil = time.Time{}
+}
+
+func (b *multiBackend) fail(min, max time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cooldown := min << uint(b.failures)
+	if cooldown > max || cooldown <= 0 {
+		cooldown = max
+	}
+	b.failures++
+	b.benchedUntil = time.Now().Add(cooldown)
+}
+
+// candidates returns healthy backends in priority order,
+// all backends are tried when every one is benched
+func (x *MultiRPC) candidates() []*multiBackend {
+	now := time.Now()
+	candidates := make([]*multiBackend, 0, len(x.backends))
+	for _, backend := range x.backends {
+		if !backend.benched(now) {
+			candidates = append(candidates, backend)
+		}
+	}
+	if len(candidates) == 0 {
+		return x.backends
+	}
+	return candidates
+}
+
+// shouldFailover reports if err means backend could not answer,
+// json-rpc errors and missing data are valid answers
+func shouldFailover(err error) bool {
+	if errors.As(err, new(EthError)) {
+		return errors.Is(err, ErrNotSupported)
+	}
+	return !errors.Is(err, ErrNotFound)
+}
+
+// isUnsupported reports if backend does not implement the method, backend is not benched for that
+func isUnsupported(err error) bool {
+	return errors.Is(err, ErrNotSupported)
+}
+
+// notSent reports if failed request surely did not reach the node: dial failures, rate limit rejections
+// and unsupported methods. Timeouts and 5xx may come after the node processed the request
+func notSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return isThrottled(err) || errors.Is(err, ErrRateLimited) || isUnsupported(err)
+}
+
+func (x *MultiRPC) do(ctx context.Context, call func(rpc EthRPC) error) error {
+	return x.failover(ctx, nil, call)
+}
+
+// failover calls backends in order until one answers, failed call is passed to the next backend
+// unless next rejects its error
+func (x *MultiRPC) failover(ctx context.Context, next func(err error) bool, call func(rpc EthRPC) error) error {
+	if len(x.backends) == 0 {
+		return fmt.Errorf("no backend")
+	}
+
+	var err error
+	for _, backend := range x.candidates() {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		err = call(backend.rpc)
+		if err == nil || !shouldFailover(err) {
+			backend.succeed()
+			return err
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		if !isUnsupported(err) {
+			backend.fail(x.minCooldown, x.maxCooldown)
+		}
+		if next != nil && !next(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// BatchCallContext sends batch request to the first healthy backend
+func (x *MultiRPC) BatchCallContext(ctx context.Context, reqs []EthRequest) ([]EthResponse, error) {
+	var result []EthResponse
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.BatchCallContext(ctx, reqs)
+		return err
+	})
+	return result, err
+}
+
+// CallContext returns raw response of method call from the first healthy backend
+func (x *MultiRPC) CallContext(ctx context.Context, method string, params ...interface{}) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.CallContext(ctx, method, params...)
+		return err
+	})
+	return result, err
+}
+
+// Web3ClientVersionContext returns the current client version.
+func (x *MultiRPC) Web3ClientVersionContext(ctx context.Context) (string, error) {
+	var result string
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.Web3ClientVersionContext(ctx)
+		return err
+	})
+	return result, err
+}
+
+// NetVersionContext returns the current network protocol version.
+func (x *MultiRPC) NetVersionContext(ctx context.Context) (string, error) {
+	var result string
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.NetVersionContext(ctx)
+		return err
+	})
+	return result, err
+}
+
+// NetListeningContext returns true if client is actively listening for network connections.
+func (x *MultiRPC) NetListeningContext(ctx context.Context) (bool, error) {
+	var result bool
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.NetListeningContext(ctx)
+		return err
+	})
+	return result, err
+}
+
+// NetPeerCountContext returns number of peers currently connected to the client.
+func (x *MultiRPC) NetPeerCountContext(ctx context.Context) (int, error) {
+	var result int
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.NetPeerCountContext(ctx)
+		return err
+	})
+	return result, err
+}
+
+// EthProtocolVersionContext returns the current ethereum protocol version.
+func (x *MultiRPC) EthProtocolVersionContext(ctx context.Context) (string, error) {
+	var result string
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthProtocolVersionContext(ctx)
+		return err
+	})
+	return result, err
+}
+
+// EthSyncingContext returns an object with data about the sync status or false.
+func (x *MultiRPC) EthSyncingContext(ctx context.Context) (*Syncing, error) {
+	var result *Syncing
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthSyncingContext(ctx)
+		return err
+	})
+	return result, err
+}
+
+// EthGasPriceContext returns the current price per gas in wei.
+func (x *MultiRPC) EthGasPriceContext(ctx context.Context) (big.Int, error) {
+	var result big.Int
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGasPriceContext(ctx)
+		return err
+	})
+	return result, err
+}
+
+// EthBlockNumberContext returns the number of most recent block.
+func (x *MultiRPC) EthBlockNumberContext(ctx context.Context) (int, error) {
+	var result int
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthBlockNumberContext(ctx)
+		return err
+	})
+	return result, err
+}
+
+// EthGetBalanceContext returns the balance of the account of given address in wei.
+func (x *MultiRPC) EthGetBalanceContext(ctx context.Context, address string, block BlockNumberOrTag) (big.Int, error) {
+	var result big.Int
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetBalanceContext(ctx, address, block)
+		return err
+	})
+	return result, err
+}
+
+// EthGetStorageAtContext returns the value from a storage position at a given address.
+func (x *MultiRPC) EthGetStorageAtContext(ctx context.Context, data string, position int, tag BlockNumberOrTag) (string, error) {
+	var result string
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetStorageAtContext(ctx, data, position, tag)
+		return err
+	})
+	return result, err
+}
+
+// EthGetTransactionCountContext returns the number of transactions sent from an address.
+func (x *MultiRPC) EthGetTransactionCountContext(ctx context.Context, address string, block BlockNumberOrTag) (int, error) {
+	var result int
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetTransactionCountContext(ctx, address, block)
+		return err
+	})
+	return result, err
+}
+
+// EthCallContext executes a new message call immediately without creating a transaction on the block chain.
+func (x *MultiRPC) EthCallContext(ctx context.Context, msg CallMsg, block BlockNumberOrTag) (string, error) {
+	var result string
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthCallContext(ctx, msg, block)
+		return err
+	})
+	return result, err
+}
+
+// EthEstimateGasContext makes a call and returns the amount of gas it takes, the call is not added to the block chain.
+func (x *MultiRPC) EthEstimateGasContext(ctx context.Context, msg CallMsg) (int, error) {
+	var result int
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthEstimateGasContext(ctx, msg)
+		return err
+	})
+	return result, err
+}
+
+// EthGetCodeContext returns code at a given address.
+func (x *MultiRPC) EthGetCodeContext(ctx context.Context, address string, block BlockNumberOrTag) (string, error) {
+	var result string
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetCodeContext(ctx, address, block)
+		return err
+	})
+	return result, err
+}
+
+// EthSendRawTransactionContext creates new message call transaction or a contract creation for signed transactions, it returns the transaction hash.
+// Transaction rejected by a backend is not sent to the next one, neither is transaction which may have
+// reached the backend before it failed with a timeout or 5xx
+func (x *MultiRPC) EthSendRawTransactionContext(ctx context.Context, data string) (string, error) {
+	var result string
+	err := x.failover(ctx, notSent, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthSendRawTransactionContext(ctx, data)
+		return err
+	})
+	return result, err
+}
+
+// EthGetBlockTransactionCountByNumberContext returns the number of transactions in a block from a block matching the given block
+func (x *MultiRPC) EthGetBlockTransactionCountByNumberContext(ctx context.Context, number BlockNumberOrTag) (int, error) {
+	var result int
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetBlockTransactionCountByNumberContext(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+// EthGetBlockTransactionCountByHashContext returns the number of transactions in a block from a block matching the given block hash.
+func (x *MultiRPC) EthGetBlockTransactionCountByHashContext(ctx context.Context, hash string) (int, error) {
+	var result int
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetBlockTransactionCountByHashContext(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+// EthGetBlockByNumberContext returns information about a block by block number.
+func (x *MultiRPC) EthGetBlockByNumberContext(ctx context.Context, number BlockNumberOrTag, withTransactions bool) (*Block, error) {
+	var result *Block
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetBlockByNumberContext(ctx, number, withTransactions)
+		return err
+	})
+	return result, err
+}
+
+// EthGetBlockByHashContext returns information about a block by block hash.
+func (x *MultiRPC) EthGetBlockByHashContext(ctx context.Context, hash string, withTransactions bool) (*Block, error) {
+	var result *Block
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetBlockByHashContext(ctx, hash, withTransactions)
+		return err
+	})
+	return result, err
+}
+
+// EthGetTransactionByHashContext returns the information about a transaction requested by transaction hash.
+func (x *MultiRPC) EthGetTransactionByHashContext(ctx context.Context, hash string) (*Transaction, error) {
+	var result *Transaction
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetTransactionByHashContext(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+// EthGetTransactionByBlockNumberAndIndexContext returns information about a transaction by block number and transaction index position.
+func (x *MultiRPC) EthGetTransactionByBlockNumberAndIndexContext(ctx context.Context, blockNumber BlockNumberOrTag, transactionIndex int) (*Transaction, error) {
+	var result *Transaction
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetTransactionByBlockNumberAndIndexContext(ctx, blockNumber, transactionIndex)
+		return err
+	})
+	return result, err
+}
+
+// EthGetTransactionByBlockHashAndIndexContext returns information about a transaction by block hash and transaction index position.
+func (x *MultiRPC) EthGetTransactionByBlockHashAndIndexContext(ctx context.Context, blockHash string, transactionIndex int) (*Transaction, error) {
+	var result *Transaction
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetTransactionByBlockHashAndIndexContext(ctx, blockHash, transactionIndex)
+		return err
+	})
+	return result, err
+}
+
+// EthGetTransactionReceiptContext returns the receipt of a transaction by transaction hash.
+// Note That the receipt is not available for pending transactions.
+func (x *MultiRPC) EthGetTransactionReceiptContext(ctx context.Context, hash string) (*TransactionReceipt, error) {
+	var result *TransactionReceipt
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetTransactionReceiptContext(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+// EthGetLogsContext returns an array of all logs matching a given filter object.
+func (x *MultiRPC) EthGetLogsContext(ctx context.Context, params FilterParams) ([]Log, error) {
+	var result []Log
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetLogsContext(ctx, params)
+		return err
+	})
+	return result, err
+}
+
+// EthGetUncleByBlockNumberAndIndexContext returns information about a uncle of a block by number and uncle index position.
+func (x *MultiRPC) EthGetUncleByBlockNumberAndIndexContext(ctx context.Context, number BlockNumberOrTag, pos int) (*Block, error) {
+	var result *Block
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetUncleByBlockNumberAndIndexContext(ctx, number, pos)
+		return err
+	})
+	return result, err
+}
+
+// EthGetUncleCountByBlockNumberContext returns the number of uncles in a block from a block matching the given block number.
+func (x *MultiRPC) EthGetUncleCountByBlockNumberContext(ctx context.Context, number BlockNumberOrTag) (int, error) {
+	var result int
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetUncleCountByBlockNumberContext(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+// EthGetUncleCountByBlockHashContext returns the number of uncles in a block from a block matching the given block hash.
+func (x *MultiRPC) EthGetUncleCountByBlockHashContext(ctx context.Context, hash string) (int, error) {
+	var result int
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetUncleCountByBlockHashContext(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+// EthGetUncleByBlockHashAndIndexContext returns information about a uncle of a block by hash and uncle index position.
+func (x *MultiRPC) EthGetUncleByBlockHashAndIndexContext(ctx context.Context, hash string, pos int) (*Block, error) {
+	var result *Block
+	err := x.do(ctx, func(rpc EthRPC) (err error) {
+		result, err = rpc.EthGetUncleByBlockHashAndIndexContext(ctx, hash, pos)
+		return err
+	})
+	return result, err
+}
@@ -0,0 +1,145 @@
+package ethrpc
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newCountingServer(status int, body string, hits *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+}
+
+func TestMultiRPCFailover(t *testing.T) {
+	var unsupportedHits, badHits, goodHits int32
+	unsupported := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`, &unsupportedHits)
+	defer unsupported.Close()
+	bad := newCountingServer(http.StatusBadGateway, "", &badHits)
+	defer bad.Close()
+	good := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0xde0b6b3a7640000"}`, &goodHits)
+	defer good.Close()
+
+	rpc := NewMultiRPC([]EthRPC{
+		NewNodeAPI(unsupported.URL),
+		NewNodeAPI(bad.URL),
+		NewNodeAPI(good.URL),
+	}, MultiRPCCooldown(50*time.Millisecond, time.Second))
+
+	// unsupported method does not bench the backend, bad node is benched
+	balance, err := rpc.EthGetBalance("0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf", "latest")
+	require.Nil(t, err)
+	require.Equal(t, "1000000000000000000", balance.String())
+	require.Equal(t, int32(1), badHits)
+	require.Equal(t, int32(1), goodHits)
+
+	_, err = rpc.EthGetBalance("0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf", "latest")
+	require.Nil(t, err)
+	require.Equal(t, int32(1), badHits)
+	require.Equal(t, int32(2), goodHits)
+
+	// cooldown passed, bad node is tried again
+	time.Sleep(60 * time.Millisecond)
+	_, err = rpc.EthGetBalance("0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf", "latest")
+	require.Nil(t, err)
+	require.Equal(t, int32(2), badHits)
+	require.Equal(t, int32(3), goodHits)
+	require.Equal(t, int32(3), unsupportedHits)
+}
+
+func TestMultiRPCEthError(t *testing.T) {
+	var firstHits, secondHits int32
+	first := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"invalid argument 0"}}`, &firstHits)
+	defer first.Close()
+	second := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x1"}`, &secondHits)
+	defer second.Close()
+
+	rpc := NewMultiRPC([]EthRPC{NewNodeAPI(first.URL), NewNodeAPI(second.URL)})
+	_, err := rpc.EthGetTransactionCount("0x", "latest")
+	require.Equal(t, EthError{Code: -32602, Message: "invalid argument 0"}, err)
+	require.Equal(t, int32(1), firstHits)
+	require.Equal(t, int32(0), secondHits)
+}
+
+func TestMultiRPCFailoverTransport(t *testing.T) {
+	var unavailableHits, goodHits int32
+	// backend which refuses connections
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	unavailable := newCountingServer(http.StatusServiceUnavailable, "", &unavailableHits)
+	defer unavailable.Close()
+	good := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x10"}`, &goodHits)
+	defer good.Close()
+
+	rpc := NewMultiRPC([]EthRPC{NewNodeAPI(down.URL), NewNodeAPI(unavailable.URL), NewNodeAPI(good.URL)})
+	number, err := rpc.EthBlockNumber()
+	require.Nil(t, err)
+	require.Equal(t, 16, number)
+	require.Equal(t, int32(1), unavailableHits)
+	require.Equal(t, int32(1), goodHits)
+}
+
+func TestMultiRPCAllFailed(t *testing.T) {
+	var badHits, unavailableHits int32
+	bad := newCountingServer(http.StatusBadGateway, "", &badHits)
+	defer bad.Close()
+	unavailable := newCountingServer(http.StatusServiceUnavailable, "", &unavailableHits)
+	defer unavailable.Close()
+
+	// error of the last backend is returned
+	rpc := NewMultiRPC([]EthRPC{NewNodeAPI(bad.URL), NewNodeAPI(unavailable.URL)})
+	_, err := rpc.EthBlockNumber()
+	require.Equal(t, "http status code error 503", err.Error())
+	require.Equal(t, int32(1), badHits)
+	require.Equal(t, int32(1), unavailableHits)
+}
+
+func TestMultiRPCSendRejected(t *testing.T) {
+	var firstHits, secondHits int32
+	first := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"nonce too low"}}`, &firstHits)
+	defer first.Close()
+	second := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1"}`, &secondHits)
+	defer second.Close()
+
+	// rejected transaction is not sent to the next backend
+	rpc := NewMultiRPC([]EthRPC{NewNodeAPI(first.URL), NewNodeAPI(second.URL)})
+	_, err := rpc.EthSendRawTransaction("0xf86c")
+	require.True(t, errors.Is(err, ErrNonceTooLow))
+	require.Equal(t, int32(1), firstHits)
+	require.Equal(t, int32(0), secondHits)
+}
+
+func TestMultiRPCSendFailover(t *testing.T) {
+	var badHits, throttledHits, goodHits int32
+	bad := newCountingServer(http.StatusBadGateway, "", &badHits)
+	defer bad.Close()
+	throttled := newCountingServer(http.StatusTooManyRequests, "", &throttledHits)
+	defer throttled.Close()
+	good := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1"}`, &goodHits)
+	defer good.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	// transaction may have reached the backend failed with 5xx, it is not sent to the next one
+	rpc := NewMultiRPC([]EthRPC{NewNodeAPI(bad.URL), NewNodeAPI(good.URL)})
+	_, err := rpc.EthSendRawTransaction("0xf86c")
+	require.Equal(t, "http status code error 502", err.Error())
+	require.Equal(t, int32(1), badHits)
+	require.Equal(t, int32(0), goodHits)
+
+	// backends which surely did not get the transaction are failed over
+	rpc = NewMultiRPC([]EthRPC{NewNodeAPI(down.URL), NewNodeAPI(throttled.URL), NewNodeAPI(good.URL)})
+	hash, err := rpc.EthSendRawTransaction("0xf86c")
+	require.Nil(t, err)
+	require.Equal(t, "0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1", hash)
+	require.Equal(t, int32(1), throttledHits)
+	require.Equal(t, int32(1), goodHits)
+}
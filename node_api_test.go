@@ -0,0 +1,152 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeAPIContextDeadline(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	rpc := NewNodeAPI(server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := rpc.EthBlockNumberContext(ctx)
+	require.NotNil(t, err)
+	require.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
+
+func TestNodeAPIBlockNumber(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x5dd091"}`))
+	}))
+	defer server.Close()
+
+	rpc := NewNodeAPI(server.URL)
+	number, err := rpc.EthBlockNumber()
+	require.Nil(t, err)
+	require.Equal(t, 6148241, number)
+}
+
+func TestNodeAPICall(t *testing.T) {
+	got := make(chan []byte, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		got <- body
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x00000000000000000000000000000000000000000000000000000000000003e8"}`))
+	}))
+	defer server.Close()
+
+	rpc := NewNodeAPI(server.URL)
+	msg := CallMsg{
+		From:  "0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf",
+		To:    "0xdac17f958d2ee523a2206206994597c13d831ec7",
+		Gas:   100000,
+		Value: big.NewInt(0),
+		Data:  "0x70a08231000000000000000000000000d10e3be2bc8f959bc8c41cf65f60de721cf89adf",
+	}
+	result, err := rpc.EthCall(msg, "latest")
+	require.Nil(t, err)
+	require.Equal(t, "0x00000000000000000000000000000000000000000000000000000000000003e8", result)
+
+	request := EthRequest{}
+	require.Nil(t, json.Unmarshal(<-got, &request))
+	require.Equal(t, "eth_call", request.Method)
+	require.Equal(t, []interface{}{map[string]interface{}{
+		"from":  "0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf",
+		"to":    "0xdac17f958d2ee523a2206206994597c13d831ec7",
+		"gas":   "0x186a0",
+		"value": "0x0",
+		"data":  "0x70a08231000000000000000000000000d10e3be2bc8f959bc8c41cf65f60de721cf89adf",
+	}, "latest"}, request.Params)
+
+	// state override is sent as the third param
+	nonce := 1
+	_, err = rpc.EthCallOverride(msg, "0x10", StateOverride{
+		"0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf": {
+			Nonce:     &nonce,
+			Balance:   big.NewInt(1000),
+			StateDiff: map[string]string{"0x0": "0x1"},
+		},
+	})
+	require.Nil(t, err)
+
+	require.Nil(t, json.Unmarshal(<-got, &request))
+	require.Len(t, request.Params, 3)
+	require.Equal(t, map[string]interface{}{
+		"0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf": map[string]interface{}{
+			"nonce":     "0x1",
+			"balance":   "0x3e8",
+			"stateDiff": map[string]interface{}{"0x0": "0x1"},
+		},
+	}, request.Params[2])
+}
+
+func TestNodeAPISendRawTransaction(t *testing.T) {
+	var hits int32
+	rejecting := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"nonce too low"}}`, &hits)
+	defer rejecting.Close()
+	accepting := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1"}`, &hits)
+	defer accepting.Close()
+
+	hash, err := NewNodeAPI(accepting.URL).EthSendRawTransaction("0xf86c")
+	require.Nil(t, err)
+	require.Equal(t, "0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1", hash)
+
+	// rejected transaction is not sent to the next backend
+	rpc := NewMultiRPC([]EthRPC{NewNodeAPI(rejecting.URL), NewNodeAPI(accepting.URL)})
+	_, err = rpc.EthSendRawTransaction("0xf86c")
+	require.True(t, errors.Is(err, ErrNonceTooLow))
+	require.Equal(t, int32(2), atomic.LoadInt32(&hits))
+}
+
+func TestNodeAPIBlockByHash(t *testing.T) {
+	got := make(chan EthRequest, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := EthRequest{}
+		json.NewDecoder(r.Body).Decode(&request)
+		got <- request
+		switch request.Method {
+		case "eth_getUncleCountByBlockHash":
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+		default:
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"number":"0x5dd091","hash":"0x8c16ed4fa1d4c9a7cd2ae3f4c6d2d9e4c48e2f1b3c2a1b0f9e8d7c6b5a4f3e2d","transactions":[]}}`))
+		}
+	}))
+	defer server.Close()
+
+	rpc := NewNodeAPI(server.URL)
+
+	block, err := rpc.EthGetBlockByNumber(BlockFinalized, false)
+	require.Nil(t, err)
+	require.Equal(t, 6148241, block.Number)
+	require.Equal(t, []interface{}{"finalized", false}, (<-got).Params)
+
+	block, err = rpc.EthGetBlockByHash("0x8c16ed4fa1d4c9a7cd2ae3f4c6d2d9e4c48e2f1b3c2a1b0f9e8d7c6b5a4f3e2d", true)
+	require.Nil(t, err)
+	require.Equal(t, "0x8c16ed4fa1d4c9a7cd2ae3f4c6d2d9e4c48e2f1b3c2a1b0f9e8d7c6b5a4f3e2d", block.Hash)
+	request := <-got
+	require.Equal(t, "eth_getBlockByHash", request.Method)
+	require.Equal(t, []interface{}{"0x8c16ed4fa1d4c9a7cd2ae3f4c6d2d9e4c48e2f1b3c2a1b0f9e8d7c6b5a4f3e2d", true}, request.Params)
+
+	count, err := rpc.EthGetUncleCountByBlockHash("0x8c16ed4fa1d4c9a7cd2ae3f4c6d2d9e4c48e2f1b3c2a1b0f9e8d7c6b5a4f3e2d")
+	require.Nil(t, err)
+	require.Equal(t, 1, count)
+	require.Equal(t, "eth_getUncleCountByBlockHash", (<-got).Method)
+}
@@ -0,0 +1,317 @@
+package ethrpc
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var errNoNode = errors.New("no node in pool")
+
+// PoolStrategy - how NodePool picks the node serving a request
+type PoolStrategy int
+
+const (
+	// RoundRobin rotates over nodes
+	RoundRobin PoolStrategy = iota
+	// LeastLatency picks the node with the lowest average latency
+	LeastLatency
+	// Weighted picks nodes randomly in proportion to their weight
+	Weighted
+)
+
+// NodePool balances requests across nodes, nodes which are syncing, failing
+// or whose head lags behind the best node are excluded until next probe
+type NodePool struct {
+	rpcClient
+
+	nodes         []*poolNode
+	strategy      PoolStrategy
+	maxLag        int
+	probeInterval time.Duration
+	probeTimeout  time.Duration
+
+	next uint64
+	quit chan struct{}
+	once sync.Once
+}
+
+// NodeStatus - state of pool node as seen by last probe
+type NodeStatus struct {
+	Node     string
+	Head     int
+	Syncing  bool
+	Healthy  bool
+	Eligible bool
+	Latency  time.Duration
+}
+
+type poolNode struct {
+	api    *NodeAPI
+	weight int
+
+	mu       sync.Mutex
+	head     int
+	syncing  bool
+	healthy  bool
+	eligible bool
+	latency  time.Duration
+}
+
+// NodePoolStrategy set node selection strategy, round robin by default
+func NodePoolStrategy(strategy PoolStrategy) func(x *NodePool) {
+	return func(x *NodePool) {
+		x.strategy = strategy
+	}
+}
+
+// NodePoolMaxLag set how many blocks node head may lag behind the best node
+func NodePoolMaxLag(blocks int) func(x *NodePool) {
+	return func(x *NodePool) {
+		x.maxLag = blocks
+	}
+}
+
+// NodePoolProbeInterval set interval of eth_blockNumber and eth_syncing probes, 15s by default,
+// non positive interval keeps the default
+func NodePoolProbeInterval(interval time.Duration) func(x *NodePool) {
+	return func(x *NodePool) {
+		if interval > 0 {
+			x.probeInterval = interval
+		}
+	}
+}
+
+// NodePoolWeights set node weights used by Weighted strategy, in the order of nodes
+func NodePoolWeights(weights ...int) func(x *NodePool) {
+	return func(x *NodePool) {
+		for i := range x.nodes {
+			if i < len(weights) {
+				x.nodes[i].weight = weights[i]
+			}
+		}
+	}
+}
+
+// NewNodePool create new rpc client balancing requests across nodes,
+// nodes are probed in background until Close
+func NewNodePool(nodes []*NodeAPI, options ...func(x *NodePool)) *NodePool {
+	pool := &NodePool{
+		strategy:      RoundRobin,
+		maxLag:        5,
+		probeInterval: 15 * time.Second,
+		probeTimeout:  5 * time.Second,
+		quit:          make(chan struct{}),
+	}
+	for _, node := range nodes {
+		pool.nodes = append(pool.nodes, &poolNode{api: node, weight: 1, healthy: true, eligible: true})
+	}
+	for _, option := range options {
+		option(pool)
+	}
+	pool.transport = &poolTransport{pool: pool}
+	pool.init()
+
+	go pool.probeLoop()
+
+	return pool
+}
+
+func (x *NodePool) String() string {
+	names := make([]string, len(x.nodes))
+	for i, node := range x.nodes {
+		names[i] = node.api.String()
+	}
+	return "pool(" + strings.Join(names, ",") + ")"
+}
+
+// Status returns state of every node
+func (x *NodePool) Status() []NodeStatus {
+	status := make([]NodeStatus, len(x.nodes))
+	for i, node := range x.nodes {
+		node.mu.Lock()
+		status[i] = NodeStatus{
+			Node:     node.api.String(),
+			Head:     node.head,
+			Syncing:  node.syncing,
+			Healthy:  node.healthy,
+			Eligible: node.eligible,
+			Latency:  node.latency,
+		}
+		node.mu.Unlock()
+	}
+	return status
+}
+
+func (x *NodePool) probeLoop() {
+	ticker := time.NewTicker(x.probeInterval)
+	defer ticker.Stop()
+
+	for {
+		x.Probe(context.Background())
+		select {
+		case <-x.quit:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Probe checks head and sync status of every node and updates eligible nodes
+func (x *NodePool) Probe(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, x.probeTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, node := range x.nodes {
+		wg.Add(1)
+		go func(node *poolNode) {
+			defer wg.Done()
+			node.probe(ctx)
+		}(node)
+	}
+	wg.Wait()
+
+	best := 0
+	for _, node := range x.nodes {
+		node.mu.Lock()
+		if node.healthy && node.head > best {
+			best = node.head
+		}
+		node.mu.Unlock()
+	}
+	for _, node := range x.nodes {
+		node.mu.Lock()
+		node.eligible = node.healthy && !node.syncing && best-node.head <= x.maxLag
+		node.mu.Unlock()
+	}
+}
+
+func (n *poolNode) probe(ctx context.Context) {
+	head, err := n.api.EthBlockNumberContext(ctx)
+	var syncing *Syncing
+	if err == nil {
+		syncing, err = n.api.EthSyncingContext(ctx)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.healthy = err == nil
+	if err == nil {
+		n.head = head
+		n.syncing = syncing.IsSyncing
+	}
+}
+
+// observe records latency of successful request, failing node is excluded until next probe
+func (n *poolNode) observe(ctx context.Context, latency time.Duration, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err != nil {
+		if !errors.As(err, new(EthError)) && ctx.Err() == nil {
+			n.healthy = false
+			n.eligible = false
+		}
+		return
+	}
+	if n.latency == 0 {
+		n.latency = latency
+	} else {
+		n.latency = (4*n.latency + latency) / 5
+	}
+}
+
+// pick returns node serving next request, all nodes are candidates when none is eligible
+func (x *NodePool) pick() *poolNode {
+	candidates := make([]*poolNode, 0, len(x.nodes))
+	for _, node := range x.nodes {
+		node.mu.Lock()
+		if node.eligible {
+			candidates = append(candidates, node)
+		}
+		node.mu.Unlock()
+	}
+	if len(candidates) == 0 {
+		candidates = x.nodes
+	}
+
+	switch x.strategy {
+	case LeastLatency:
+		var best *poolNode
+		var bestLatency time.Duration
+		for _, node := range candidates {
+			node.mu.Lock()
+			latency := node.latency
+			node.mu.Unlock()
+			if best == nil || latency < bestLatency {
+				best, bestLatency = node, latency
+			}
+		}
+		return best
+	case Weighted:
+		total := 0
+		for _, node := range candidates {
+			total += node.weight
+		}
+		if total > 0 {
+			n := rand.Intn(total)
+			for _, node := range candidates {
+				if n -= node.weight; n < 0 {
+					return node
+				}
+			}
+		}
+	}
+
+	next := atomic.AddUint64(&x.next, 1)
+	return candidates[next%uint64(len(candidates))]
+}
+
+// poolTransport delegates every request to transport of picked node
+type poolTransport struct {
+	pool *NodePool
+}
+
+func (x *poolTransport) Call(ctx context.Context, request EthRequest) (*EthResponse, error) {
+	if len(x.pool.nodes) == 0 {
+		return nil, errNoNode
+	}
+	node := x.pool.pick()
+
+	start := time.Now()
+	resp, err := node.api.transport.Call(ctx, request)
+	node.observe(ctx, time.Since(start), err)
+	return resp, err
+}
+
+func (x *poolTransport) BatchCall(ctx context.Context, requests []EthRequest) ([]EthResponse, error) {
+	if len(x.pool.nodes) == 0 {
+		return nil, errNoNode
+	}
+	node := x.pool.pick()
+
+	start := time.Now()
+	resps, err := node.api.transport.BatchCall(ctx, requests)
+	node.observe(ctx, time.Since(start), err)
+	return resps, err
+}
+
+func (x *poolTransport) Debug(debug bool) {
+	for _, node := range x.pool.nodes {
+		node.api.Debug(debug)
+	}
+}
+
+// Close stops probing, nodes are not closed
+func (x *poolTransport) Close() error {
+	x.pool.once.Do(func() {
+		close(x.pool.quit)
+	})
+	return nil
+}
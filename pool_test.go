@@ -0,0 +1,95 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newFakeNode(head int, version string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req EthRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		resp := EthResponse{ID: req.ID, Version: "2.0"}
+		switch req.Method {
+		case "eth_blockNumber":
+			resp.Result = json.RawMessage(`"` + IntToHex(head) + `"`)
+		case "eth_syncing":
+			resp.Result = json.RawMessage(`false`)
+		default:
+			resp.Result = json.RawMessage(`"` + version + `"`)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestNodePoolExcludesLaggingNode(t *testing.T) {
+	first := newFakeNode(100, "first")
+	defer first.Close()
+	second := newFakeNode(98, "second")
+	defer second.Close()
+	lagging := newFakeNode(80, "lagging")
+	defer lagging.Close()
+
+	pool := NewNodePool([]*NodeAPI{
+		NewNodeAPI(first.URL),
+		NewNodeAPI(second.URL),
+		NewNodeAPI(lagging.URL),
+	}, NodePoolMaxLag(5), NodePoolProbeInterval(time.Hour))
+	defer pool.Close()
+	pool.Probe(context.Background())
+
+	status := pool.Status()
+	require.True(t, status[0].Eligible)
+	require.True(t, status[1].Eligible)
+	require.False(t, status[2].Eligible)
+	require.Equal(t, 80, status[2].Head)
+
+	seen := map[string]int{}
+	for i := 0; i < 10; i++ {
+		version, err := pool.Web3ClientVersion()
+		require.Nil(t, err)
+		seen[version]++
+	}
+	require.Equal(t, map[string]int{"first": 5, "second": 5}, seen)
+}
+
+func TestNodePoolWeighted(t *testing.T) {
+	first := newFakeNode(100, "first")
+	defer first.Close()
+	second := newFakeNode(100, "second")
+	defer second.Close()
+
+	pool := NewNodePool([]*NodeAPI{
+		NewNodeAPI(first.URL),
+		NewNodeAPI(second.URL),
+	}, NodePoolStrategy(Weighted), NodePoolWeights(1, 0), NodePoolProbeInterval(time.Hour))
+	defer pool.Close()
+
+	for i := 0; i < 5; i++ {
+		version, err := pool.Web3ClientVersion()
+		require.Nil(t, err)
+		require.Equal(t, "first", version)
+	}
+}
+
+func TestNodePoolProbeInterval(t *testing.T) {
+	node := newFakeNode(100, "node")
+	defer node.Close()
+
+	// non positive interval keeps the default instead of panicking in the probe loop
+	for _, interval := range []time.Duration{0, -time.Second} {
+		pool := NewNodePool([]*NodeAPI{NewNodeAPI(node.URL)}, NodePoolProbeInterval(interval))
+		require.Equal(t, 15*time.Second, pool.probeInterval)
+		version, err := pool.Web3ClientVersion()
+		require.Nil(t, err)
+		require.Equal(t, "node", version)
+		pool.Close()
+	}
+}
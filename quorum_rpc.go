@@ -0,0 +1,667 @@
+package ethrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// QuorumRPC fans every call out to all backends concurrently
+// and returns once quorum of them agree on the result
+type QuorumRPC struct {
+	backgroundRPC
+
+	backends []EthRPC
+	quorum   int
+}
+
+// NewQuorumRPC create new rpc client requiring quorum of backends to agree,
+// non positive quorum requires majority of backends and quorum above backends count requires all of them
+func NewQuorumRPC(quorum int, backends []EthRPC) *QuorumRPC {
+	if quorum <= 0 {
+		quorum = len(backends)/2 + 1
+	}
+	if quorum > len(backends) {
+		quorum = len(backends)
+	}
+	rpc := &QuorumRPC{
+		backends: backends,
+		quorum:   quorum,
+	}
+	rpc.backgroundRPC = backgroundRPC{rpc}
+
+	return rpc
+}
+
+func (x *QuorumRPC) String() string {
+	names := make([]string, len(x.backends))
+	for i, backend := range x.backends {
+		names[i] = backend.String()
+	}
+	return fmt.Sprintf("quorum-%d(%s)", x.quorum, strings.Join(names, ","))
+}
+
+func (x *QuorumRPC) Debug(debug bool) {
+	for _, backend := range x.backends {
+		backend.Debug(debug)
+	}
+}
+
+// QuorumResult - answer of a single backend
+type QuorumResult struct {
+	Backend string
+	Value   interface{}
+	Err     error
+}
+
+// ErrNotAnswered - backend had not answered when quorum was decided, its call is cancelled
+var ErrNotAnswered = errors.New("not answered, cancelled")
+
+// DisagreementError - backends did not reach quorum, Results lists the answer of every backend
+// in the order of backends, backends which had not answered yet have ErrNotAnswered
+type DisagreementError struct {
+	Method  string
+	Quorum  int
+	Results []QuorumResult
+}
+
+func (err *DisagreementError) Error() string {
+	answers := make([]string, len(err.Results))
+	for i, result := range err.Results {
+		if result.Err != nil {
+			answers[i] = fmt.Sprintf("%s error %s", result.Backend, result.Err)
+		} else {
+			answers[i] = fmt.Sprintf("%s %s", result.Backend, formatQuorumValue(result.Value))
+		}
+	}
+	return fmt.Sprintf("%s quorum %d not reached: %s", err.Method, err.Quorum, strings.Join(answers, "; "))
+}
+
+func formatQuorumValue(value interface{}) string {
+	switch v := value.(type) {
+	case big.Int:
+		return v.String()
+	case *Block:
+		if v != nil {
+			return "block " + v.Hash
+		}
+	case json.RawMessage:
+		return string(v)
+	}
+	return fmt.Sprintf("%+v", value)
+}
+
+type quorumGroup struct {
+	result QuorumResult
+	count  int
+}
+
+// read runs call on every backend, answers are grouped with equal,
+// errors which are valid answers (json-rpc errors, not found) vote as well
+func (x *QuorumRPC) read(ctx context.Context, method string, equal func(a, b interface{}) bool, call func(ctx context.Context, rpc EthRPC) (interface{}, error)) (interface{}, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type answer struct {
+		index  int
+		result QuorumResult
+	}
+	answers := make(chan answer, len(x.backends))
+	received := make([]QuorumResult, len(x.backends))
+	for i, backend := range x.backends {
+		received[i] = QuorumResult{Backend: backend.String(), Err: ErrNotAnswered}
+		go func(i int, backend EthRPC) {
+			value, err := call(ctx, backend)
+			answers <- answer{i, QuorumResult{Backend: backend.String(), Value: value, Err: err}}
+		}(i, backend)
+	}
+
+	var groups []*quorumGroup
+	best := 0
+	for pending := len(x.backends); pending > 0; pending-- {
+		var result QuorumResult
+		select {
+		case answer := <-answers:
+			result = answer.result
+			received[answer.index] = result
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if result.Err != nil && shouldFailover(result.Err) {
+			if best+pending-1 < x.quorum {
+				break
+			}
+			continue
+		}
+
+		var group *quorumGroup
+		for _, g := range groups {
+			if quorumEqual(equal, g.result, result) {
+				group = g
+				break
+			}
+		}
+		if group == nil {
+			group = &quorumGroup{result: result}
+			groups = append(groups, group)
+		}
+		group.count++
+
+		if group.count >= x.quorum {
+			return group.result.Value, group.result.Err
+		}
+		if group.count > best {
+			best = group.count
+		}
+		if best+pending-1 < x.quorum {
+			break
+		}
+	}
+
+	return nil, &DisagreementError{
+		Method:  method,
+		Quorum:  x.quorum,
+		Results: received,
+	}
+}
+
+func quorumEqual(equal func(a, b interface{}) bool, a, b QuorumResult) bool {
+	if a.Err != nil || b.Err != nil {
+		return a.Err != nil && b.Err != nil && a.Err.Error() == b.Err.Error()
+	}
+	return equal(a.Value, b.Value)
+}
+
+func bigEqual(a, b interface{}) bool {
+	x, y := a.(big.Int), b.(big.Int)
+	return x.Cmp(&y) == 0
+}
+
+func blockEqual(a, b interface{}) bool {
+	x, y := a.(*Block), b.(*Block)
+	if x == nil || y == nil {
+		return x == y
+	}
+	return x.Hash == y.Hash
+}
+
+func intPtrEqual(x, y *int) bool {
+	if x == nil || y == nil {
+		return x == y
+	}
+	return *x == *y
+}
+
+func transactionEqual(a, b interface{}) bool {
+	x, y := a.(*Transaction), b.(*Transaction)
+	if x == nil || y == nil {
+		return x == y
+	}
+	return x.Hash == y.Hash &&
+		x.Nonce == y.Nonce &&
+		x.BlockHash == y.BlockHash &&
+		intPtrEqual(x.BlockNumber, y.BlockNumber) &&
+		intPtrEqual(x.TransactionIndex, y.TransactionIndex) &&
+		x.From == y.From &&
+		x.To == y.To &&
+		x.Value.Cmp(&y.Value) == 0 &&
+		x.Gas == y.Gas &&
+		x.GasPrice.Cmp(&y.GasPrice) == 0 &&
+		x.Input == y.Input &&
+		x.Type == y.Type &&
+		bigPtrEqual(x.ChainID, y.ChainID) &&
+		bigPtrEqual(x.MaxFeePerGas, y.MaxFeePerGas) &&
+		bigPtrEqual(x.MaxPriorityFeePerGas, y.MaxPriorityFeePerGas) &&
+		accessListEqual(x.AccessList, y.AccessList) &&
+		x.V.Cmp(&y.V) == 0 &&
+		x.R.Cmp(&y.R) == 0 &&
+		x.S.Cmp(&y.S) == 0 &&
+		bigPtrEqual(x.MaxFeePerBlobGas, y.MaxFeePerBlobGas) &&
+		stringsEqual(x.BlobVersionedHashes, y.BlobVersionedHashes)
+}
+
+// stringsEqual treats nil and empty lists as equal, backends differ on omitting empty lists
+func stringsEqual(x, y []string) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func accessListEqual(x, y []AccessTuple) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i].Address != y[i].Address || !stringsEqual(x[i].StorageKeys, y[i].StorageKeys) {
+			return false
+		}
+	}
+	return true
+}
+
+func bigPtrEqual(x, y *big.Int) bool {
+	if x == nil || y == nil {
+		return x == y
+	}
+	return x.Cmp(y) == 0
+}
+
+func logsEqual(x, y []Log) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if !reflect.DeepEqual(x[i], y[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func receiptEqual(a, b interface{}) bool {
+	x, y := a.(*TransactionReceipt), b.(*TransactionReceipt)
+	if x == nil || y == nil {
+		return x == y
+	}
+	return x.TransactionHash == y.TransactionHash &&
+		x.TransactionIndex == y.TransactionIndex &&
+		x.BlockHash == y.BlockHash &&
+		x.BlockNumber == y.BlockNumber &&
+		x.CumulativeGasUsed == y.CumulativeGasUsed &&
+		x.GasUsed == y.GasUsed &&
+		x.ContractAddress == y.ContractAddress &&
+		logsEqual(x.Logs, y.Logs) &&
+		x.LogsBloom == y.LogsBloom &&
+		x.Root == y.Root &&
+		x.Status == y.Status &&
+		x.EffectiveGasPrice.Cmp(&y.EffectiveGasPrice) == 0 &&
+		x.Type == y.Type &&
+		x.From == y.From &&
+		x.To == y.To &&
+		x.BlobGasUsed == y.BlobGasUsed &&
+		bigPtrEqual(x.BlobGasPrice, y.BlobGasPrice)
+}
+
+func rawEqual(a, b interface{}) bool {
+	x, y := new(bytes.Buffer), new(bytes.Buffer)
+	if json.Compact(x, a.(json.RawMessage)) != nil || json.Compact(y, b.(json.RawMessage)) != nil {
+		return bytes.Equal(a.(json.RawMessage), b.(json.RawMessage))
+	}
+	return bytes.Equal(x.Bytes(), y.Bytes())
+}
+
+func responsesEqual(a, b interface{}) bool {
+	x, y := a.([]EthResponse), b.([]EthResponse)
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if !reflect.DeepEqual(x[i].Error, y[i].Error) || !rawEqual(x[i].Result, y[i].Result) {
+			return false
+		}
+	}
+	return true
+}
+
+// BatchCallContext sends batch request to every backend
+func (x *QuorumRPC) BatchCallContext(ctx context.Context, reqs []EthRequest) ([]EthResponse, error) {
+	result, err := x.read(ctx, "BatchCall", responsesEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.BatchCallContext(ctx, reqs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]EthResponse), nil
+}
+
+// CallContext returns raw response of method call agreed by quorum
+func (x *QuorumRPC) CallContext(ctx context.Context, method string, params ...interface{}) (json.RawMessage, error) {
+	result, err := x.read(ctx, "Call", rawEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.CallContext(ctx, method, params...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(json.RawMessage), nil
+}
+
+// Web3ClientVersionContext returns the current client version.
+func (x *QuorumRPC) Web3ClientVersionContext(ctx context.Context) (string, error) {
+	result, err := x.read(ctx, "Web3ClientVersion", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.Web3ClientVersionContext(ctx)
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+// NetVersionContext returns the current network protocol version.
+func (x *QuorumRPC) NetVersionContext(ctx context.Context) (string, error) {
+	result, err := x.read(ctx, "NetVersion", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.NetVersionContext(ctx)
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+// NetListeningContext returns true if client is actively listening for network connections.
+func (x *QuorumRPC) NetListeningContext(ctx context.Context) (bool, error) {
+	result, err := x.read(ctx, "NetListening", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.NetListeningContext(ctx)
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.(bool), nil
+}
+
+// NetPeerCountContext returns number of peers currently connected to the client.
+func (x *QuorumRPC) NetPeerCountContext(ctx context.Context) (int, error) {
+	result, err := x.read(ctx, "NetPeerCount", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.NetPeerCountContext(ctx)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+// EthProtocolVersionContext returns the current ethereum protocol version.
+func (x *QuorumRPC) EthProtocolVersionContext(ctx context.Context) (string, error) {
+	result, err := x.read(ctx, "EthProtocolVersion", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthProtocolVersionContext(ctx)
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+// EthSyncingContext returns an object with data about the sync status or false.
+func (x *QuorumRPC) EthSyncingContext(ctx context.Context) (*Syncing, error) {
+	result, err := x.read(ctx, "EthSyncing", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthSyncingContext(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Syncing), nil
+}
+
+// EthGasPriceContext returns the current price per gas in wei.
+func (x *QuorumRPC) EthGasPriceContext(ctx context.Context) (big.Int, error) {
+	result, err := x.read(ctx, "EthGasPrice", bigEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGasPriceContext(ctx)
+	})
+	if err != nil {
+		return big.Int{}, err
+	}
+	return result.(big.Int), nil
+}
+
+// EthBlockNumberContext returns the number of most recent block.
+func (x *QuorumRPC) EthBlockNumberContext(ctx context.Context) (int, error) {
+	result, err := x.read(ctx, "EthBlockNumber", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthBlockNumberContext(ctx)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+// EthGetBalanceContext returns the balance of the account of given address in wei.
+func (x *QuorumRPC) EthGetBalanceContext(ctx context.Context, address string, block BlockNumberOrTag) (big.Int, error) {
+	result, err := x.read(ctx, "EthGetBalance", bigEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetBalanceContext(ctx, address, block)
+	})
+	if err != nil {
+		return big.Int{}, err
+	}
+	return result.(big.Int), nil
+}
+
+// EthGetStorageAtContext returns the value from a storage position at a given address.
+func (x *QuorumRPC) EthGetStorageAtContext(ctx context.Context, data string, position int, tag BlockNumberOrTag) (string, error) {
+	result, err := x.read(ctx, "EthGetStorageAt", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetStorageAtContext(ctx, data, position, tag)
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+// EthGetTransactionCountContext returns the number of transactions sent from an address.
+func (x *QuorumRPC) EthGetTransactionCountContext(ctx context.Context, address string, block BlockNumberOrTag) (int, error) {
+	result, err := x.read(ctx, "EthGetTransactionCount", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetTransactionCountContext(ctx, address, block)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+// EthCallContext executes a new message call immediately without creating a transaction on the block chain.
+func (x *QuorumRPC) EthCallContext(ctx context.Context, msg CallMsg, block BlockNumberOrTag) (string, error) {
+	result, err := x.read(ctx, "EthCall", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthCallContext(ctx, msg, block)
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+// EthEstimateGasContext makes a call and returns the amount of gas it takes, the call is not added to the block chain.
+func (x *QuorumRPC) EthEstimateGasContext(ctx context.Context, msg CallMsg) (int, error) {
+	result, err := x.read(ctx, "EthEstimateGas", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthEstimateGasContext(ctx, msg)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+// EthGetCodeContext returns code at a given address.
+func (x *QuorumRPC) EthGetCodeContext(ctx context.Context, address string, block BlockNumberOrTag) (string, error) {
+	result, err := x.read(ctx, "EthGetCode", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetCodeContext(ctx, address, block)
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+// EthSendRawTransactionContext creates new message call transaction or a contract creation for signed transactions, it returns the transaction hash.
+// The transaction is broadcast to every backend, it is sent once any backend accepts it
+func (x *QuorumRPC) EthSendRawTransactionContext(ctx context.Context, data string) (string, error) {
+	type sent struct {
+		hash string
+		err  error
+	}
+	results := make(chan sent, len(x.backends))
+	for _, backend := range x.backends {
+		go func(backend EthRPC) {
+			hash, err := backend.EthSendRawTransactionContext(ctx, data)
+			results <- sent{hash, err}
+		}(backend)
+	}
+
+	var err error
+	for range x.backends {
+		result := <-results
+		if result.err == nil {
+			return result.hash, nil
+		}
+		// rejection of transaction tells more than failure to reach backend
+		if err == nil || errors.As(result.err, new(EthError)) {
+			err = result.err
+		}
+	}
+	if err == nil {
+		err = fmt.Errorf("no backend")
+	}
+	return "", err
+}
+
+// EthGetBlockTransactionCountByNumberContext returns the number of transactions in a block from a block matching the given block
+func (x *QuorumRPC) EthGetBlockTransactionCountByNumberContext(ctx context.Context, number BlockNumberOrTag) (int, error) {
+	result, err := x.read(ctx, "EthGetBlockTransactionCountByNumber", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetBlockTransactionCountByNumberContext(ctx, number)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+// EthGetBlockTransactionCountByHashContext returns the number of transactions in a block from a block matching the given block hash.
+func (x *QuorumRPC) EthGetBlockTransactionCountByHashContext(ctx context.Context, hash string) (int, error) {
+	result, err := x.read(ctx, "EthGetBlockTransactionCountByHash", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetBlockTransactionCountByHashContext(ctx, hash)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+// EthGetBlockByNumberContext returns information about a block by block number.
+func (x *QuorumRPC) EthGetBlockByNumberContext(ctx context.Context, number BlockNumberOrTag, withTransactions bool) (*Block, error) {
+	result, err := x.read(ctx, "EthGetBlockByNumber", blockEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetBlockByNumberContext(ctx, number, withTransactions)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Block), nil
+}
+
+// EthGetBlockByHashContext returns information about a block by block hash.
+func (x *QuorumRPC) EthGetBlockByHashContext(ctx context.Context, hash string, withTransactions bool) (*Block, error) {
+	result, err := x.read(ctx, "EthGetBlockByHash", blockEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetBlockByHashContext(ctx, hash, withTransactions)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Block), nil
+}
+
+// EthGetTransactionByHashContext returns the information about a transaction requested by transaction hash.
+func (x *QuorumRPC) EthGetTransactionByHashContext(ctx context.Context, hash string) (*Transaction, error) {
+	result, err := x.read(ctx, "EthGetTransactionByHash", transactionEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetTransactionByHashContext(ctx, hash)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Transaction), nil
+}
+
+// EthGetTransactionByBlockNumberAndIndexContext returns information about a transaction by block number and transaction index position.
+func (x *QuorumRPC) EthGetTransactionByBlockNumberAndIndexContext(ctx context.Context, blockNumber BlockNumberOrTag, transactionIndex int) (*Transaction, error) {
+	result, err := x.read(ctx, "EthGetTransactionByBlockNumberAndIndex", transactionEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetTransactionByBlockNumberAndIndexContext(ctx, blockNumber, transactionIndex)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Transaction), nil
+}
+
+// EthGetTransactionByBlockHashAndIndexContext returns information about a transaction by block hash and transaction index position.
+func (x *QuorumRPC) EthGetTransactionByBlockHashAndIndexContext(ctx context.Context, blockHash string, transactionIndex int) (*Transaction, error) {
+	result, err := x.read(ctx, "EthGetTransactionByBlockHashAndIndex", transactionEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetTransactionByBlockHashAndIndexContext(ctx, blockHash, transactionIndex)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Transaction), nil
+}
+
+// EthGetTransactionReceiptContext returns the receipt of a transaction by transaction hash.
+// Note That the receipt is not available for pending transactions.
+func (x *QuorumRPC) EthGetTransactionReceiptContext(ctx context.Context, hash string) (*TransactionReceipt, error) {
+	result, err := x.read(ctx, "EthGetTransactionReceipt", receiptEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetTransactionReceiptContext(ctx, hash)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*TransactionReceipt), nil
+}
+
+// EthGetLogsContext returns an array of all logs matching a given filter object.
+func (x *QuorumRPC) EthGetLogsContext(ctx context.Context, params FilterParams) ([]Log, error) {
+	result, err := x.read(ctx, "EthGetLogs", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetLogsContext(ctx, params)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]Log), nil
+}
+
+// EthGetUncleByBlockNumberAndIndexContext returns information about a uncle of a block by number and uncle index position.
+func (x *QuorumRPC) EthGetUncleByBlockNumberAndIndexContext(ctx context.Context, number BlockNumberOrTag, pos int) (*Block, error) {
+	result, err := x.read(ctx, "EthGetUncleByBlockNumberAndIndex", blockEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetUncleByBlockNumberAndIndexContext(ctx, number, pos)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Block), nil
+}
+
+// EthGetUncleCountByBlockNumberContext returns the number of uncles in a block from a block matching the given block number.
+func (x *QuorumRPC) EthGetUncleCountByBlockNumberContext(ctx context.Context, number BlockNumberOrTag) (int, error) {
+	result, err := x.read(ctx, "EthGetUncleCountByBlockNumber", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetUncleCountByBlockNumberContext(ctx, number)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+// EthGetUncleCountByBlockHashContext returns the number of uncles in a block from a block matching the given block hash.
+func (x *QuorumRPC) EthGetUncleCountByBlockHashContext(ctx context.Context, hash string) (int, error) {
+	result, err := x.read(ctx, "EthGetUncleCountByBlockHash", reflect.DeepEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetUncleCountByBlockHashContext(ctx, hash)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+// EthGetUncleByBlockHashAndIndexContext returns information about a uncle of a block by hash and uncle index position.
+func (x *QuorumRPC) EthGetUncleByBlockHashAndIndexContext(ctx context.Context, hash string, pos int) (*Block, error) {
+	result, err := x.read(ctx, "EthGetUncleByBlockHashAndIndex", blockEqual, func(ctx context.Context, rpc EthRPC) (interface{}, error) {
+		return rpc.EthGetUncleByBlockHashAndIndexContext(ctx, hash, pos)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Block), nil
+}
@@ -0,0 +1,146 @@
+package ethrpc
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuorumRPC(t *testing.T) {
+	var hits int32
+	first := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x64"}`, &hits)
+	defer first.Close()
+	second := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x0064"}`, &hits)
+	defer second.Close()
+	third := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x65"}`, &hits)
+	defer third.Close()
+	broken := newCountingServer(http.StatusInternalServerError, ``, &hits)
+	defer broken.Close()
+
+	backends := []EthRPC{NewNodeAPI(first.URL), NewNodeAPI(broken.URL), NewNodeAPI(second.URL), NewNodeAPI(third.URL)}
+
+	balance, err := NewQuorumRPC(2, backends).EthGetBalance("0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf", "latest")
+	require.Nil(t, err)
+	require.Equal(t, int64(100), balance.Int64())
+
+	_, err = NewQuorumRPC(4, backends).EthGetBalance("0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf", "latest")
+	disagreement, ok := err.(*DisagreementError)
+	require.True(t, ok)
+	require.Equal(t, "EthGetBalance", disagreement.Method)
+	require.Len(t, disagreement.Results, 4)
+
+	// every backend is reported in the order of backends
+	require.Equal(t, "node-"+first.URL, disagreement.Results[0].Backend)
+	require.Equal(t, "node-"+broken.URL, disagreement.Results[1].Backend)
+	require.Equal(t, "node-"+second.URL, disagreement.Results[2].Backend)
+	require.Equal(t, "node-"+third.URL, disagreement.Results[3].Backend)
+	// a backend failed, quorum of all backends can not be reached once it answered
+	require.NotNil(t, disagreement.Results[1].Err)
+	require.NotEqual(t, ErrNotAnswered, disagreement.Results[1].Err)
+}
+
+func TestQuorumRPCNotAnswered(t *testing.T) {
+	done := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer slow.Close()
+	defer close(done)
+
+	var hits int32
+	first := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x64"}`, &hits)
+	defer first.Close()
+	second := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x65"}`, &hits)
+	defer second.Close()
+
+	// disagreement of two backends is decided before the slow one answers
+	_, err := NewQuorumRPC(3, []EthRPC{NewNodeAPI(first.URL), NewNodeAPI(slow.URL), NewNodeAPI(second.URL)}).EthGetBalance("0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf", "latest")
+	disagreement, ok := err.(*DisagreementError)
+	require.True(t, ok)
+	require.Len(t, disagreement.Results, 3)
+
+	value := disagreement.Results[0].Value.(big.Int)
+	require.Equal(t, int64(100), value.Int64())
+	require.Equal(t, QuorumResult{Backend: "node-" + slow.URL, Err: ErrNotAnswered}, disagreement.Results[1])
+	value = disagreement.Results[2].Value.(big.Int)
+	require.Equal(t, int64(101), value.Int64())
+	require.Contains(t, err.Error(), "not answered")
+}
+
+func TestQuorumRPCBlock(t *testing.T) {
+	var hits int32
+	block := `{"jsonrpc":"2.0","id":1,"result":{"number":"0x10","hash":"0x8c16ed4fa1d4c9a7cd2ae3f4c6d2d9e4c48e2f1b3c2a1b0f9e8d7c6b5a4f3e2d","difficulty":"0x1","transactions":[]}}`
+	first := newCountingServer(http.StatusOK, block, &hits)
+	defer first.Close()
+	second := newCountingServer(http.StatusOK, block, &hits)
+	defer second.Close()
+	reorged := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":{"number":"0x10","hash":"0x1f0e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c4b5a69788796a5b4c3d2e1f0","difficulty":"0x1","transactions":[]}}`, &hits)
+	defer reorged.Close()
+
+	backends := []EthRPC{NewNodeAPI(first.URL), NewNodeAPI(reorged.URL), NewNodeAPI(second.URL)}
+
+	result, err := NewQuorumRPC(2, backends).EthGetBlockByNumber(BlockNumber(16), false)
+	require.Nil(t, err)
+	require.Equal(t, "0x8c16ed4fa1d4c9a7cd2ae3f4c6d2d9e4c48e2f1b3c2a1b0f9e8d7c6b5a4f3e2d", result.Hash)
+
+	_, err = NewQuorumRPC(3, backends).EthGetBlockByNumber(BlockNumber(16), false)
+	_, ok := err.(*DisagreementError)
+	require.True(t, ok)
+}
+
+func TestQuorumRPCReceipt(t *testing.T) {
+	var hits int32
+	receipt := `{"jsonrpc":"2.0","id":1,"result":{"transactionHash":"0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1","blockNumber":"0x10","gasUsed":"0x5208","effectiveGasPrice":"0x3b9aca00","status":"0x1","logs":[]}}`
+	first := newCountingServer(http.StatusOK, receipt, &hits)
+	defer first.Close()
+	// same receipt with zero padded numbers and without logs decodes to equal fields
+	padded := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":{"transactionHash":"0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1","blockNumber":"0x0010","gasUsed":"0x5208","effectiveGasPrice":"0x003b9aca00","status":"0x1"}}`, &hits)
+	defer padded.Close()
+	failed := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":{"transactionHash":"0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1","blockNumber":"0x10","gasUsed":"0x5208","effectiveGasPrice":"0x3b9aca00","status":"0x0","logs":[]}}`, &hits)
+	defer failed.Close()
+
+	result, err := NewQuorumRPC(2, []EthRPC{NewNodeAPI(first.URL), NewNodeAPI(failed.URL), NewNodeAPI(padded.URL)}).EthGetTransactionReceipt("0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1")
+	require.Nil(t, err)
+	require.Equal(t, 1, result.Status)
+	require.Equal(t, "1000000000", result.EffectiveGasPrice.String())
+
+	_, err = NewQuorumRPC(2, []EthRPC{NewNodeAPI(first.URL), NewNodeAPI(failed.URL)}).EthGetTransactionReceipt("0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1")
+	_, ok := err.(*DisagreementError)
+	require.True(t, ok)
+}
+
+func TestQuorumRPCTransaction(t *testing.T) {
+	var hits int32
+	transaction := func(maxFeePerGas string) string {
+		return `{"jsonrpc":"2.0","id":1,"result":{"hash":"0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1","nonce":"0x1","blockHash":null,"blockNumber":null,"transactionIndex":null,"from":"0x3b0b3e2b8e0c4bd2d5b0e4e8e9d09b0f1b3e5c7a","to":"0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf","value":"0x0","gas":"0x5208","gasPrice":"0x3b9aca00","input":"0x","type":"0x2","chainId":"0x1","maxFeePerGas":"` + maxFeePerGas + `","maxPriorityFeePerGas":"0x3b9aca00","accessList":[],"v":"0x0","r":"0x1","s":"0x2"}}`
+	}
+	first := newCountingServer(http.StatusOK, transaction("0x77359400"), &hits)
+	defer first.Close()
+	second := newCountingServer(http.StatusOK, transaction("0x77359400"), &hits)
+	defer second.Close()
+	// differs only on max fee per gas
+	third := newCountingServer(http.StatusOK, transaction("0xb2d05e00"), &hits)
+	defer third.Close()
+
+	result, err := NewQuorumRPC(2, []EthRPC{NewNodeAPI(first.URL), NewNodeAPI(third.URL), NewNodeAPI(second.URL)}).EthGetTransactionByHash("0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1")
+	require.Nil(t, err)
+	require.Equal(t, "2000000000", result.MaxFeePerGas.String())
+
+	_, err = NewQuorumRPC(2, []EthRPC{NewNodeAPI(first.URL), NewNodeAPI(third.URL)}).EthGetTransactionByHash("0x1e2910a262b1008d0616a0beb24c1a491d78771baa54a33e66065e03b1f46bc1")
+	_, ok := err.(*DisagreementError)
+	require.True(t, ok)
+}
+
+func TestQuorumRPCQuorum(t *testing.T) {
+	backends := []EthRPC{NewNodeAPI("http://first"), NewNodeAPI("http://second"), NewNodeAPI("http://third"), NewNodeAPI("http://fourth")}
+
+	// majority of backends by default, all of them at most
+	require.Equal(t, 3, NewQuorumRPC(0, backends).quorum)
+	require.Equal(t, 3, NewQuorumRPC(-1, backends).quorum)
+	require.Equal(t, 2, NewQuorumRPC(2, backends).quorum)
+	require.Equal(t, 4, NewQuorumRPC(5, backends).quorum)
+	require.Equal(t, 2, NewQuorumRPC(0, backends[:2]).quorum)
+}
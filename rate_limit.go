@@ -0,0 +1,157 @@
+package ethrpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimit bounds requests sent to backend with a token bucket,
+// every request of a batch takes a token
+type RateLimit struct {
+	// PerSecond is the rate tokens are refilled at, requests are not limited when it is not positive
+	PerSecond float64
+	// Burst is the capacity of bucket, PerSecond rounded up by default
+	Burst int
+	// NoWait fails with *RateLimitError instead of waiting when the budget is exhausted
+	NoWait bool
+}
+
+// RateLimitError - request rejected by client side rate limit
+type RateLimitError struct {
+	Requests   int
+	RetryAfter time.Duration
+}
+
+// Is reports the error as ErrRateLimited
+func (err *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+func (err *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded by %d requests, retry after %s", err.Requests, err.RetryAfter)
+}
+
+// RateLimitUsage - state of rate limiter
+type RateLimitUsage struct {
+	// Available is the number of tokens in bucket, negative when requests are waiting
+	Available float64
+	// Requests is the number of requests admitted
+	Requests int64
+	// Rejected is the number of requests failed with *RateLimitError
+	Rejected int64
+	// Waited is the total time requests waited for tokens
+	Waited time.Duration
+}
+
+type rateLimiter struct {
+	rate   float64
+	burst  float64
+	noWait bool
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	usage  RateLimitUsage
+}
+
+// newRateLimiter returns nil limiter, which admits every request, for non positive rate
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	if limit.PerSecond <= 0 {
+		return nil
+	}
+	burst := float64(limit.Burst)
+	if burst <= 0 {
+		burst = math.Ceil(limit.PerSecond)
+	}
+	return &rateLimiter{
+		rate:   limit.PerSecond,
+		burst:  burst,
+		noWait: limit.NoWait,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// refill adds tokens earned since last refill, must be called with lock held
+func (l *rateLimiter) refill(now time.Time) {
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+}
+
+// reserve returns how long n tokens are waited for, must be called with lock held
+func (l *rateLimiter) reserve(n int) time.Duration {
+	need := math.Min(float64(n), l.burst)
+	return time.Duration((need - l.tokens) / l.rate * float64(time.Second))
+}
+
+// delay returns how long n tokens would be waited for without taking them
+func (l *rateLimiter) delay(n int) time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(time.Now())
+	return l.reserve(n)
+}
+
+// wait takes n tokens, waiting until they are refilled unless limiter does not wait.
+// Tokens are reserved ahead so waiting requests are served in order,
+// a batch larger than burst is admitted once the bucket is full
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	l.refill(time.Now())
+
+	delay := l.reserve(n)
+	if delay > 0 && l.noWait {
+		l.usage.Rejected += int64(n)
+		l.mu.Unlock()
+		return &RateLimitError{Requests: n, RetryAfter: delay}
+	}
+	l.tokens -= float64(n)
+	l.usage.Requests += int64(n)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens += float64(n)
+		l.usage.Requests -= int64(n)
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		l.mu.Lock()
+		l.usage.Waited += delay
+		l.mu.Unlock()
+		return nil
+	}
+}
+
+func (l *rateLimiter) stat() RateLimitUsage {
+	if l == nil {
+		return RateLimitUsage{}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(time.Now())
+	usage := l.usage
+	usage.Available = l.tokens
+	return usage
+}
@@ -0,0 +1,102 @@
+package ethrpc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitWait(t *testing.T) {
+	var hits int32
+	server := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x1"}`, &hits)
+	defer server.Close()
+
+	rpc := NewNodeAPI(server.URL, NodeRateLimit(RateLimit{PerSecond: 20, Burst: 2}))
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := rpc.EthBlockNumber()
+		require.Nil(t, err)
+	}
+	// 2 requests of burst, then 2 more refilled at 50ms each
+	require.True(t, time.Since(start) >= 90*time.Millisecond)
+	require.Equal(t, int32(4), hits)
+
+	usage := rpc.RateLimitUsage()
+	require.Equal(t, int64(4), usage.Requests)
+	require.Equal(t, int64(0), usage.Rejected)
+	require.True(t, usage.Waited >= 90*time.Millisecond)
+
+	// waiting is canceled with context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := rpc.BatchCallContext(ctx, []EthRequest{
+		NewEthRequest("eth_blockNumber"),
+		NewEthRequest("eth_blockNumber"),
+		NewEthRequest("eth_blockNumber"),
+	})
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.Equal(t, int32(4), hits)
+	require.Equal(t, int64(4), rpc.RateLimitUsage().Requests)
+}
+
+func TestRateLimitNoWait(t *testing.T) {
+	var hits int32
+	server := newCountingServer(http.StatusOK, `[{"jsonrpc":"2.0","id":1,"result":"0x1"},{"jsonrpc":"2.0","id":2,"result":"0x2"}]`, &hits)
+	defer server.Close()
+
+	rpc := NewNodeAPI(server.URL, NodeRateLimit(RateLimit{PerSecond: 1, Burst: 3, NoWait: true}))
+
+	batch := NewBatch(rpc)
+	batch.EthBlockNumber()
+	batch.EthBlockNumber()
+	require.Nil(t, batch.Execute())
+
+	// batch takes a token per request
+	_, err := rpc.BatchCall([]EthRequest{NewEthRequest("eth_blockNumber"), NewEthRequest("eth_blockNumber")})
+	limitErr, ok := err.(*RateLimitError)
+	require.True(t, ok)
+	require.Equal(t, 2, limitErr.Requests)
+	require.True(t, limitErr.RetryAfter > 500*time.Millisecond)
+	require.Equal(t, int32(1), hits)
+
+	usage := rpc.RateLimitUsage()
+	require.Equal(t, int64(2), usage.Requests)
+	require.Equal(t, int64(2), usage.Rejected)
+	require.InDelta(t, 1, usage.Available, 0.1)
+
+	require.Equal(t, RateLimitUsage{}, NewNodeAPI(server.URL).RateLimitUsage())
+}
+
+func TestRateLimitNotPositive(t *testing.T) {
+	var hits int32
+	server := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x1"}`, &hits)
+	defer server.Close()
+
+	// non positive rate does not limit requests
+	for _, rate := range []float64{0, -1} {
+		rpc := NewNodeAPI(server.URL, NodeRateLimit(RateLimit{PerSecond: rate, NoWait: true}))
+		for i := 0; i < 10; i++ {
+			_, err := rpc.EthBlockNumber()
+			require.Nil(t, err)
+		}
+		require.Equal(t, RateLimitUsage{}, rpc.RateLimitUsage())
+	}
+	require.Equal(t, int32(20), hits)
+
+	etherscan := newEtherscanServer(map[string]string{
+		"proxy/eth_blockNumber": `{"jsonrpc":"2.0","id":1,"result":"0x10"}`,
+	}, nil)
+	defer etherscan.Close()
+
+	rpc := NewEtherscanAPI("key", EtherscanURL(etherscan.URL), EtherscanKeyRate(0))
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		_, err := rpc.EthBlockNumber()
+		require.Nil(t, err)
+	}
+	require.True(t, time.Since(start) < time.Second)
+}
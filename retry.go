@@ -0,0 +1,122 @@
+package ethrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy retries failed requests with exponential backoff and jitter,
+// the zero value does not retry
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first one
+	MaxAttempts int
+	// MinBackoff is the delay before second attempt, doubled on every next attempt
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay, Retry-After of the response is honored over it
+	MaxBackoff time.Duration
+	// Retryable reports if err is transient, IsRetryable by default
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy retries 3 times starting at 200ms backoff
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  200 * time.Millisecond,
+		MaxBackoff:  5 * time.Second,
+	}
+}
+
+// IsRetryable reports if err is transient: http 429 and 5xx statuses,
+// connection resets, timeouts and json-rpc limit exceeded error -32005
+func IsRetryable(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+	var ethErr EthError
+	if errors.As(err, &ethErr) {
+		return ethErr.Code == CodeLimitExceeded
+	}
+
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF || err == errConnectionLost {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return false
+}
+
+// isThrottled reports if err rejected the request before it was processed: http 429 and json-rpc limit exceeded error -32005
+func isThrottled(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	var ethErr EthError
+	return errors.As(err, &ethErr) && ethErr.Code == CodeLimitExceeded
+}
+
+// restrict returns policy which retries only the retryable errors accepted by retry
+func (p RetryPolicy) restrict(retry func(err error) bool) RetryPolicy {
+	restricted := p
+	restricted.Retryable = func(err error) bool {
+		return retry(err) && p.retryable(err)
+	}
+	return restricted
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryable(err)
+}
+
+// backoff returns delay after given attempt
+func (p RetryPolicy) backoff(attempt int, err error) time.Duration {
+	delay := p.MinBackoff << uint(attempt-1)
+	if delay > p.MaxBackoff || delay <= 0 {
+		delay = p.MaxBackoff
+	}
+	if delay > 0 {
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.RetryAfter > delay {
+		delay = httpErr.RetryAfter
+	}
+	return delay
+}
+
+// do calls call until it succeeds, fails with error which is not retryable or attempts run out,
+// attempt starts at 1
+func (p RetryPolicy) do(ctx context.Context, call func(attempt int) error) error {
+	for attempt := 1; ; attempt++ {
+		err := call(attempt)
+		if err == nil || attempt >= p.MaxAttempts || ctx.Err() != nil || !p.retryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(p.backoff(attempt, err)):
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package ethrpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&hits, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"limit exceeded"}}`))
+		default:
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+		}
+	}))
+	defer server.Close()
+
+	rpc := NewNodeAPI(server.URL, NodeRetry(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+
+	start := time.Now()
+	number, err := rpc.EthBlockNumber()
+	require.Nil(t, err)
+	require.Equal(t, 16, number)
+	require.Equal(t, int32(3), hits)
+	require.True(t, time.Since(start) >= time.Second, "Retry-After is honored")
+}
+
+func TestRetryPolicyNotRetryable(t *testing.T) {
+	var hits int32
+	server := newCountingServer(http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"invalid argument 0"}}`, &hits)
+	defer server.Close()
+
+	rpc := NewNodeAPI(server.URL, NodeRetry(DefaultRetryPolicy()))
+	_, err := rpc.EthBlockNumber()
+	require.Equal(t, EthError{Code: -32602, Message: "invalid argument 0"}, err)
+	require.Equal(t, int32(1), hits)
+
+	// zero policy does not retry
+	var failHits int32
+	fail := newCountingServer(http.StatusBadGateway, "", &failHits)
+	defer fail.Close()
+
+	rpc = NewNodeAPI(fail.URL)
+	_, err = rpc.EthBlockNumber()
+	require.Equal(t, "http status code error 502", err.Error())
+	require.Equal(t, int32(1), failHits)
+}
+
+func TestEtherscanRetryRotatesKeys(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("apikey")
+		keys = append(keys, key)
+		if key != "c" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+	defer server.Close()
+
+	rpc := NewEtherscanAPI("a,b,c", EtherscanURL(server.URL), EtherscanRetry(RetryPolicy{MaxAttempts: 3}))
+
+	number, err := rpc.EthBlockNumber()
+	require.Nil(t, err)
+	require.Equal(t, 16, number)
+	require.Equal(t, []string{"a", "b", "c"}, keys)
+}
+
+func TestRetryPolicyRawTransaction(t *testing.T) {
+	var hits int32
+	server := newCountingServer(http.StatusBadGateway, "", &hits)
+	defer server.Close()
+
+	policy := RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	// send may have reached the node, it is not resent
+	_, err := NewNodeAPI(server.URL, NodeRetry(policy)).EthSendRawTransaction("0xf86c")
+	require.Equal(t, "http status code error 502", err.Error())
+	require.Equal(t, int32(1), hits)
+
+	var throttledHits int32
+	throttled := newCountingServer(http.StatusTooManyRequests, "", &throttledHits)
+	defer throttled.Close()
+
+	// throttled send was not processed, it is retried
+	_, err = NewNodeAPI(throttled.URL, NodeRetry(policy)).EthSendRawTransaction("0xf86c")
+	require.Equal(t, "http status code error 429", err.Error())
+	require.Equal(t, int32(3), throttledHits)
+
+	var etherscanHits int32
+	etherscan := newCountingServer(http.StatusBadGateway, "", &etherscanHits)
+	defer etherscan.Close()
+
+	_, err = NewEtherscanAPI("a,b", EtherscanURL(etherscan.URL), EtherscanRetry(policy)).EthSendRawTransaction("0xf86c")
+	require.NotNil(t, err)
+	require.Equal(t, int32(1), etherscanHits)
+}
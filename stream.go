@@ -0,0 +1,515 @@
+package ethrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	errConnectionLost  = errors.New("connection lost")
+	errTransportClosed = errors.New("transport closed")
+)
+
+// streamConn is a full duplex connection carrying json-rpc messages
+type streamConn interface {
+	Write(ctx context.Context, data []byte) error
+	Read() ([]byte, error)
+	Close() error
+}
+
+type streamResult struct {
+	resps []EthResponse
+	err   error
+}
+
+// pendingCall waits for the response of a request or a batch,
+// ids maps the transport request ids to the caller ones
+type pendingCall struct {
+	ids      map[int]int
+	ch       chan streamResult
+	onResult func(resps []EthResponse)
+}
+
+// streamTransport multiplexes requests and subscriptions over a single connection,
+// the connection is dialed lazily and redialed when subscriptions are active
+type streamTransport struct {
+	name string
+	dial func(ctx context.Context) (streamConn, error)
+
+	// reconnect backoff, doubled after each failed attempt
+	minReconnectDelay time.Duration
+	maxReconnectDelay time.Duration
+
+	mu           sync.Mutex
+	writeMu      sync.Mutex
+	debug        bool
+	conn         streamConn
+	nextID       int
+	pending      map[int]*pendingCall
+	subs         map[*Subscription]struct{}
+	subIDs       map[string]*Subscription
+	reconnecting bool
+	closed       bool
+	closeCh      chan struct{}
+}
+
+func newStreamTransport(name string, dial func(ctx context.Context) (streamConn, error)) streamTransport {
+	return streamTransport{
+		name:              name,
+		dial:              dial,
+		minReconnectDelay: 500 * time.Millisecond,
+		maxReconnectDelay: 30 * time.Second,
+		pending:           make(map[int]*pendingCall),
+		subs:              make(map[*Subscription]struct{}),
+		subIDs:            make(map[string]*Subscription),
+		closeCh:           make(chan struct{}),
+	}
+}
+
+func (x *streamTransport) Debug(debug bool) {
+	x.mu.Lock()
+	x.debug = debug
+	x.mu.Unlock()
+}
+
+func (x *streamTransport) debugging() bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.debug
+}
+
+// Close closes the connection, pending calls and subscriptions fail with error
+func (x *streamTransport) Close() error {
+	x.mu.Lock()
+	if x.closed {
+		x.mu.Unlock()
+		return nil
+	}
+	x.closed = true
+	close(x.closeCh)
+	conn := x.conn
+	subs := make([]*Subscription, 0, len(x.subs))
+	for sub := range x.subs {
+		subs = append(subs, sub)
+	}
+	x.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.stop(errTransportClosed)
+	}
+	if conn != nil {
+		x.fail(conn, errTransportClosed)
+	}
+	return nil
+}
+
+// connect returns the connection, dialing it outside of lock when there is none,
+// connection dialed concurrently by another call wins and the redundant one is closed
+func (x *streamTransport) connect(ctx context.Context) (streamConn, error) {
+	x.mu.Lock()
+	closed, conn := x.closed, x.conn
+	x.mu.Unlock()
+	if closed {
+		return nil, errTransportClosed
+	}
+	if conn != nil {
+		return conn, nil
+	}
+
+	conn, err := x.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	x.mu.Lock()
+	switch {
+	case x.closed:
+		x.mu.Unlock()
+		conn.Close()
+		return nil, errTransportClosed
+	case x.conn != nil:
+		current := x.conn
+		x.mu.Unlock()
+		conn.Close()
+		return current, nil
+	}
+	x.conn = conn
+	x.mu.Unlock()
+
+	go x.readLoop(conn)
+	return conn, nil
+}
+
+func (x *streamTransport) readLoop(conn streamConn) {
+	for {
+		data, err := conn.Read()
+		if err != nil {
+			x.fail(conn, err)
+			return
+		}
+		if x.debugging() {
+			fmt.Printf("%s receive %s\n", x.name, data)
+		}
+		x.handle(data)
+	}
+}
+
+// fail drops broken connection, pending calls receive the error
+// and active subscriptions are restored on a new connection
+func (x *streamTransport) fail(conn streamConn, err error) {
+	x.mu.Lock()
+	if x.conn != conn {
+		x.mu.Unlock()
+		return
+	}
+	x.conn = nil
+	pending := x.pending
+	x.pending = make(map[int]*pendingCall)
+	reconnect := len(x.subs) > 0 && !x.closed && !x.reconnecting
+	if reconnect {
+		x.reconnecting = true
+	}
+	x.mu.Unlock()
+
+	conn.Close()
+	notified := make(map[*pendingCall]bool)
+	for _, p := range pending {
+		if !notified[p] {
+			notified[p] = true
+			p.ch <- streamResult{err: err}
+		}
+	}
+
+	if reconnect {
+		go x.reconnect()
+	}
+}
+
+func (x *streamTransport) reconnect() {
+	delay := x.minReconnectDelay
+	for {
+		select {
+		case <-x.closeCh:
+			return
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > x.maxReconnectDelay {
+			delay = x.maxReconnectDelay
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		conn, err := x.connect(ctx)
+		if err == nil {
+			err = x.resubscribe(ctx)
+		}
+		cancel()
+		if err != nil {
+			if x.debugging() {
+				fmt.Printf("%s reconnect error %s\n", x.name, err)
+			}
+			continue
+		}
+
+		x.mu.Lock()
+		done := x.conn == conn
+		if done {
+			x.reconnecting = false
+		}
+		x.mu.Unlock()
+		if done {
+			return
+		}
+	}
+}
+
+func (x *streamTransport) resubscribe(ctx context.Context) error {
+	x.mu.Lock()
+	subs := make([]*Subscription, 0, len(x.subs))
+	for sub := range x.subs {
+		subs = append(subs, sub)
+	}
+	x.mu.Unlock()
+
+	for _, sub := range subs {
+		if err := x.subscribe(ctx, sub); err != nil {
+			if !errors.As(err, new(EthError)) {
+				return err
+			}
+			sub.stop(err)
+		}
+	}
+	return nil
+}
+
+type subscriptionNotification struct {
+	Method string `json:"method"`
+	Params struct {
+		Subscription string          `json:"subscription"`
+		Result       json.RawMessage `json:"result"`
+	} `json:"params"`
+}
+
+func (x *streamTransport) handle(data []byte) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var resps []EthResponse
+		if err := json.Unmarshal(data, &resps); err != nil || len(resps) == 0 {
+			return
+		}
+		x.respondBatch(resps)
+		return
+	}
+
+	notification := new(subscriptionNotification)
+	if err := json.Unmarshal(data, notification); err != nil {
+		return
+	}
+	if strings.HasSuffix(notification.Method, "_subscription") {
+		x.notify(notification.Params.Subscription, notification.Params.Result)
+		return
+	}
+
+	resp := EthResponse{}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return
+	}
+	if !x.respond(resp.ID, []EthResponse{resp}) && resp.Error != nil {
+		x.respondUnmatched(*resp.Error)
+	}
+}
+
+// respondBatch matches batch response by id of any of its responses, error responses of
+// requests the node could not parse have null id
+func (x *streamTransport) respondBatch(resps []EthResponse) {
+	for _, resp := range resps {
+		if x.respond(resp.ID, resps) {
+			return
+		}
+	}
+	for _, resp := range resps {
+		if resp.Error != nil {
+			x.respondUnmatched(*resp.Error)
+			return
+		}
+	}
+}
+
+// respondUnmatched fails the pending call with error response which has no id of a pending request,
+// the response is dropped when several calls are pending and it can not be told which one failed
+func (x *streamTransport) respondUnmatched(err EthError) {
+	x.mu.Lock()
+	var p *pendingCall
+	for _, pending := range x.pending {
+		if p != nil && p != pending {
+			x.mu.Unlock()
+			return
+		}
+		p = pending
+	}
+	if p == nil {
+		x.mu.Unlock()
+		return
+	}
+	for id := range p.ids {
+		delete(x.pending, id)
+	}
+	x.mu.Unlock()
+
+	p.ch <- streamResult{err: err}
+}
+
+// respond delivers responses to the call waiting for request id, it reports if there is such call
+func (x *streamTransport) respond(id int, resps []EthResponse) bool {
+	x.mu.Lock()
+	p, ok := x.pending[id]
+	if !ok {
+		x.mu.Unlock()
+		return false
+	}
+	for id := range p.ids {
+		delete(x.pending, id)
+	}
+	if p.onResult != nil {
+		p.onResult(resps)
+	}
+	x.mu.Unlock()
+
+	for i := range resps {
+		resps[i].ID = p.ids[resps[i].ID]
+	}
+	p.ch <- streamResult{resps: resps}
+	return true
+}
+
+func (x *streamTransport) notify(id string, result json.RawMessage) {
+	x.mu.Lock()
+	sub, ok := x.subIDs[id]
+	x.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	select {
+	case sub.queue <- result:
+	default:
+		sub.stop(errSubscriptionQueueOverflow)
+	}
+}
+
+func (x *streamTransport) forget(p *pendingCall) {
+	x.mu.Lock()
+	for id := range p.ids {
+		delete(x.pending, id)
+	}
+	x.mu.Unlock()
+}
+
+// onResult runs in the read loop with lock held, before the next message is handled
+func (x *streamTransport) send(ctx context.Context, requests []EthRequest, batch bool, onResult func(resps []EthResponse)) ([]EthResponse, error) {
+	conn, err := x.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pendingCall{
+		ids:      make(map[int]int, len(requests)),
+		ch:       make(chan streamResult, 1),
+		onResult: onResult,
+	}
+	reqs := make([]EthRequest, len(requests))
+
+	x.mu.Lock()
+	if x.conn != conn {
+		x.mu.Unlock()
+		return nil, errConnectionLost
+	}
+	for i, request := range requests {
+		x.nextID++
+		p.ids[x.nextID] = request.ID
+		x.pending[x.nextID] = p
+		request.ID = x.nextID
+		reqs[i] = request
+	}
+	x.mu.Unlock()
+
+	var body []byte
+	if batch {
+		body, err = json.Marshal(reqs)
+	} else {
+		body, err = json.Marshal(reqs[0])
+	}
+	if err == nil {
+		if x.debugging() {
+			fmt.Printf("%s send %s\n", x.name, body)
+		}
+		x.writeMu.Lock()
+		err = conn.Write(ctx, body)
+		x.writeMu.Unlock()
+	}
+	if err != nil {
+		x.forget(p)
+		return nil, err
+	}
+
+	select {
+	case result := <-p.ch:
+		return result.resps, result.err
+	case <-ctx.Done():
+		x.forget(p)
+		return nil, ctx.Err()
+	}
+}
+
+// Call sends single request
+func (x *streamTransport) Call(ctx context.Context, request EthRequest) (*EthResponse, error) {
+	resps, err := x.send(ctx, []EthRequest{request}, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resps[0], nil
+}
+
+// BatchCall sends batch request
+func (x *streamTransport) BatchCall(ctx context.Context, requests []EthRequest) ([]EthResponse, error) {
+	if len(requests) == 0 {
+		return []EthResponse{}, nil
+	}
+	return x.send(ctx, requests, true, nil)
+}
+
+// Subscribe creates subscription with namespace_subscribe method,
+// deliver is called with every notification result
+func (x *streamTransport) Subscribe(ctx context.Context, namespace string, deliver deliverFunc, params ...interface{}) (*Subscription, error) {
+	sub := newSubscription(x, namespace, deliver, params)
+	if err := x.subscribe(ctx, sub); err != nil {
+		x.removeSubscription(sub)
+		return nil, err
+	}
+
+	go sub.run()
+	return sub, nil
+}
+
+func (x *streamTransport) subscribe(ctx context.Context, sub *Subscription) error {
+	request := EthRequest{
+		Version: "2.0",
+		Method:  sub.namespace + "_subscribe",
+		Params:  sub.params,
+	}
+
+	var subErr error
+	_, err := x.send(ctx, []EthRequest{request}, false, func(resps []EthResponse) {
+		// register subscription before its first notification is handled
+		if resps[0].Error != nil {
+			subErr = *resps[0].Error
+			return
+		}
+		var id string
+		if subErr = json.Unmarshal(resps[0].Result, &id); subErr != nil {
+			return
+		}
+		select {
+		case <-sub.quit:
+			return
+		default:
+		}
+		delete(x.subIDs, sub.id)
+		sub.id = id
+		x.subIDs[id] = sub
+		x.subs[sub] = struct{}{}
+	})
+	if err != nil {
+		return err
+	}
+	return subErr
+}
+
+// removeSubscription returns the server id of removed subscription
+func (x *streamTransport) removeSubscription(sub *Subscription) string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	id := sub.id
+	delete(x.subIDs, id)
+	delete(x.subs, sub)
+	return id
+}
+
+func (x *streamTransport) unsubscribe(namespace, id string) {
+	x.mu.Lock()
+	connected := x.conn != nil
+	x.mu.Unlock()
+	if !connected {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	x.send(ctx, []EthRequest{{Version: "2.0", Method: namespace + "_unsubscribe", Params: []interface{}{id}}}, false, nil)
+}
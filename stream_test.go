@@ -0,0 +1,104 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStreamConn passes written messages to answer, its replies are read from the connection
+type fakeStreamConn struct {
+	answer  func(data []byte) string
+	replies chan []byte
+	closed  chan struct{}
+}
+
+func newFakeStreamConn(answer func(data []byte) string) *fakeStreamConn {
+	return &fakeStreamConn{answer: answer, replies: make(chan []byte, 10), closed: make(chan struct{})}
+}
+
+func (c *fakeStreamConn) Write(ctx context.Context, data []byte) error {
+	c.replies <- []byte(c.answer(data))
+	return nil
+}
+
+func (c *fakeStreamConn) Read() ([]byte, error) {
+	select {
+	case data := <-c.replies:
+		return data, nil
+	case <-c.closed:
+		return nil, errConnectionLost
+	}
+}
+
+func (c *fakeStreamConn) Close() error {
+	select {
+	case <-c.closed:
+	default:
+		close(c.closed)
+	}
+	return nil
+}
+
+func TestStreamTransportUnmatchedError(t *testing.T) {
+	conn := newFakeStreamConn(func(data []byte) string {
+		var reqs []EthRequest
+		if json.Unmarshal(data, &reqs) == nil {
+			// first request of batch can not be parsed
+			return `[{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"invalid request"}},{"jsonrpc":"2.0","id":` + strconv.Itoa(reqs[1].ID) + `,"result":"0x10"}]`
+		}
+		return `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error"}}`
+	})
+	transport := newStreamTransport("fake", func(ctx context.Context) (streamConn, error) {
+		return conn, nil
+	})
+	defer transport.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// batch is matched by id of its other response
+	resps, err := transport.BatchCall(ctx, []EthRequest{NewEthRequest("eth_blockNumber"), NewEthRequest("eth_gasPrice")})
+	require.Nil(t, err)
+	require.Len(t, resps, 2)
+	require.Equal(t, json.RawMessage(`"0x10"`), resps[1].Result)
+
+	// error response without id fails the only pending call
+	_, err = transport.Call(ctx, NewEthRequest("eth_blockNumber"))
+	require.Equal(t, EthError{Code: -32700, Message: "parse error"}, err)
+	require.Nil(t, ctx.Err())
+}
+
+func TestStreamTransportConnect(t *testing.T) {
+	var dials int32
+	dialing := make(chan struct{})
+	release := make(chan struct{})
+	transport := newStreamTransport("fake", func(ctx context.Context) (streamConn, error) {
+		if atomic.AddInt32(&dials, 1) == 1 {
+			close(dialing)
+		}
+		<-release
+		return newFakeStreamConn(func(data []byte) string {
+			return `{"jsonrpc":"2.0","id":1,"result":"0x10"}`
+		}), nil
+	})
+
+	connected := make(chan error, 1)
+	go func() {
+		_, err := transport.connect(context.Background())
+		connected <- err
+	}()
+	<-dialing
+
+	// lock is not held while dialing
+	transport.Debug(false)
+	require.Nil(t, transport.Close())
+	close(release)
+	require.True(t, errors.Is(<-connected, errTransportClosed))
+}
@@ -0,0 +1,150 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// notifications buffered per subscription before it is dropped
+const subscriptionQueueSize = 4096
+
+var (
+	errSubscriptionQueueOverflow = errors.New("subscription queue overflow")
+	errSubscriptionNotSupported  = errors.New("transport does not support subscriptions")
+)
+
+// deliverFunc decodes notification result and sends it to subscriber,
+// sending must give up when quit is closed
+type deliverFunc func(result json.RawMessage, quit <-chan struct{}) error
+
+// subscriber is implemented by transports supporting eth_subscribe
+type subscriber interface {
+	Subscribe(ctx context.Context, namespace string, deliver deliverFunc, params ...interface{}) (*Subscription, error)
+}
+
+// Subscription - active eth_subscribe subscription,
+// it is restored automatically when transport reconnects
+type Subscription struct {
+	transport *streamTransport
+	namespace string
+	params    []interface{}
+	deliver   deliverFunc
+
+	// server side id, guarded by transport lock
+	id string
+
+	queue chan json.RawMessage
+	err   chan error
+	quit  chan struct{}
+	once  sync.Once
+}
+
+func newSubscription(transport *streamTransport, namespace string, deliver deliverFunc, params []interface{}) *Subscription {
+	return &Subscription{
+		transport: transport,
+		namespace: namespace,
+		params:    params,
+		deliver:   deliver,
+		queue:     make(chan json.RawMessage, subscriptionQueueSize),
+		err:       make(chan error, 1),
+		quit:      make(chan struct{}),
+	}
+}
+
+// Err returns channel receiving the error which ended subscription,
+// it is closed when subscription ends
+func (s *Subscription) Err() <-chan error {
+	return s.err
+}
+
+// Unsubscribe stops notifications delivery and cancels subscription on server
+func (s *Subscription) Unsubscribe() {
+	s.stop(nil)
+}
+
+func (s *Subscription) stop(err error) {
+	s.once.Do(func() {
+		close(s.quit)
+		if id := s.transport.removeSubscription(s); id != "" {
+			go s.transport.unsubscribe(s.namespace, id)
+		}
+		if err != nil {
+			s.err <- err
+		}
+		close(s.err)
+	})
+}
+
+func (s *Subscription) run() {
+	for {
+		select {
+		case result := <-s.queue:
+			if err := s.deliver(result, s.quit); err != nil {
+				s.stop(err)
+				return
+			}
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (x *rpcClient) subscribe(ctx context.Context, deliver deliverFunc, params ...interface{}) (*Subscription, error) {
+	transport, ok := x.transport.(subscriber)
+	if !ok {
+		return nil, errSubscriptionNotSupported
+	}
+
+	return transport.Subscribe(ctx, "eth", deliver, params...)
+}
+
+// SubscribeNewHeads sends header of every new block to ch, transactions are not included.
+func (x *rpcClient) SubscribeNewHeads(ctx context.Context, ch chan<- *Block) (*Subscription, error) {
+	return x.subscribe(ctx, func(result json.RawMessage, quit <-chan struct{}) error {
+		header := new(ProxyBlockWithoutTransactions)
+		if err := json.Unmarshal(result, header); err != nil {
+			return err
+		}
+
+		block := header.ToBlock()
+		select {
+		case ch <- &block:
+		case <-quit:
+		}
+		return nil
+	}, "newHeads")
+}
+
+// SubscribeLogs sends every new log matching a given filter object to ch.
+func (x *rpcClient) SubscribeLogs(ctx context.Context, params FilterParams, ch chan<- Log) (*Subscription, error) {
+	return x.subscribe(ctx, func(result json.RawMessage, quit <-chan struct{}) error {
+		var log Log
+		if err := json.Unmarshal(result, &log); err != nil {
+			return err
+		}
+
+		select {
+		case ch <- log:
+		case <-quit:
+		}
+		return nil
+	}, "logs", params)
+}
+
+// SubscribeNewPendingTransactions sends hash of every transaction added to the pending state to ch.
+func (x *rpcClient) SubscribeNewPendingTransactions(ctx context.Context, ch chan<- string) (*Subscription, error) {
+	return x.subscribe(ctx, func(result json.RawMessage, quit <-chan struct{}) error {
+		var hash string
+		if err := json.Unmarshal(result, &hash); err != nil {
+			return err
+		}
+
+		select {
+		case ch <- hash:
+		case <-quit:
+		}
+		return nil
+	}, "newPendingTransactions")
+}
@@ -0,0 +1,173 @@
+package ethrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Transport exchanges json-rpc requests with a backend,
+// it owns request encoding, the wire exchange and response decoding
+type Transport interface {
+	Call(ctx context.Context, request EthRequest) (*EthResponse, error)
+	BatchCall(ctx context.Context, requests []EthRequest) ([]EthResponse, error)
+	Debug(bool)
+	Close() error
+}
+
+// newTransport picks transport by url scheme, http(s) by default
+func newTransport(url string, client *http.Client, auth HTTPAuth) Transport {
+	if strings.HasPrefix(url, "unix://") || strings.HasSuffix(url, ".ipc") {
+		return NewIPCTransport(url)
+	}
+	if strings.HasPrefix(url, "ws://") || strings.HasPrefix(url, "wss://") {
+		options := []func(x *WebSocketTransport){}
+		if auth != nil {
+			options = append(options, WebSocketTransportAuth(auth))
+		}
+		return NewWebSocketTransport(url, options...)
+	}
+
+	return NewHTTPTransport(url, HTTPTransportClient(client), HTTPTransportAuth(auth))
+}
+
+// HTTPAuth adds backend credentials to outgoing http request
+type HTTPAuth func(req *http.Request)
+
+// BasicAuth authenticates requests with http basic auth
+func BasicAuth(username, password string) HTTPAuth {
+	return func(req *http.Request) {
+		req.SetBasicAuth(username, password)
+	}
+}
+
+// BearerAuth authenticates requests with bearer token
+func BearerAuth(token string) HTTPAuth {
+	return HeaderAuth("Authorization", "Bearer "+token)
+}
+
+// HeaderAuth authenticates requests with a custom header, e.g. api key header
+func HeaderAuth(key, value string) HTTPAuth {
+	return func(req *http.Request) {
+		req.Header.Set(key, value)
+	}
+}
+
+// HTTPTransport sends json-rpc requests with http POST
+type HTTPTransport struct {
+	url    string
+	client *http.Client
+	auth   HTTPAuth
+	debug  bool
+}
+
+// HTTPTransportClient set http client used by transport
+func HTTPTransportClient(client *http.Client) func(x *HTTPTransport) {
+	return func(x *HTTPTransport) {
+		x.client = client
+	}
+}
+
+// HTTPTransportAuth set auth policy used by transport
+func HTTPTransportAuth(auth HTTPAuth) func(x *HTTPTransport) {
+	return func(x *HTTPTransport) {
+		x.auth = auth
+	}
+}
+
+// NewHTTPTransport create new http transport with given url
+func NewHTTPTransport(url string, options ...func(x *HTTPTransport)) *HTTPTransport {
+	transport := &HTTPTransport{
+		url:    url,
+		client: http.DefaultClient,
+	}
+	for _, option := range options {
+		option(transport)
+	}
+
+	return transport
+}
+
+func (x *HTTPTransport) Debug(debug bool) {
+	x.debug = debug
+}
+
+func (x *HTTPTransport) Close() error {
+	x.client.CloseIdleConnections()
+	return nil
+}
+
+func (x *HTTPTransport) post(ctx context.Context, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", x.url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if x.auth != nil {
+		x.auth(req)
+	}
+
+	response, err := x.client.Do(req)
+	if response != nil {
+		defer response.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, newHTTPError(response)
+	}
+
+	return ioutil.ReadAll(response.Body)
+}
+
+// Call sends single request
+func (x *HTTPTransport) Call(ctx context.Context, request EthRequest) (*EthResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := x.post(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if x.debug {
+		fmt.Printf("request %s %s response %s\n", request.Method, body, data)
+	}
+
+	resp := new(EthResponse)
+	if err := json.Unmarshal(data, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BatchCall sends batch request
+func (x *HTTPTransport) BatchCall(ctx context.Context, requests []EthRequest) ([]EthResponse, error) {
+	body, err := json.Marshal(requests)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := x.post(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if x.debug {
+		fmt.Printf("requests %s responses %s\n", body, data)
+		fmt.Printf("requests size %d responses size %d\n", len(body), len(data))
+	}
+
+	resps := &[]EthResponse{}
+	if err := json.Unmarshal(data, resps); err != nil {
+		return nil, err
+	}
+	return *resps, nil
+}
@@ -0,0 +1,51 @@
+package ethrpc
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPTransportAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"Geth/v1.8.18"}`))
+	}))
+	defer server.Close()
+
+	rpc := NewNodeAPI(server.URL)
+	_, err := rpc.Web3ClientVersion()
+	require.NotNil(t, err)
+	require.Equal(t, "http status code error 401", err.Error())
+
+	rpc = NewNodeAPI(server.URL, NodeAuth(BearerAuth("secret")))
+	version, err := rpc.Web3ClientVersion()
+	require.Nil(t, err)
+	require.Equal(t, "Geth/v1.8.18", version)
+}
+
+func TestHTTPTransportBatchCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		require.Equal(t, `[{"id":1,"jsonrpc":"2.0","method":"eth_blockNumber","params":[]},{"id":2,"jsonrpc":"2.0","method":"net_version","params":[]}]`, string(body))
+		w.Write([]byte(`[{"jsonrpc":"2.0","id":1,"result":"0x1"},{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"method not found"}}]`))
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(server.URL)
+	resps, err := transport.BatchCall(context.Background(), []EthRequest{
+		{ID: 1, Version: "2.0", Method: "eth_blockNumber", Params: []interface{}{}},
+		{ID: 2, Version: "2.0", Method: "net_version", Params: []interface{}{}},
+	})
+	require.Nil(t, err)
+	require.Len(t, resps, 2)
+	require.Equal(t, `"0x1"`, string(resps[0].Result))
+	require.Equal(t, -32601, resps[1].Error.Code)
+}
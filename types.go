@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"math/big"
-	"unsafe"
+	"strings"
 )
 
 // Syncing - object with syncing data info
@@ -22,8 +22,12 @@ func (s *Syncing) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	proxy.IsSyncing = true
-	*s = *(*Syncing)(unsafe.Pointer(proxy))
+	*s = Syncing{
+		IsSyncing:     true,
+		StartingBlock: int(proxy.StartingBlock),
+		CurrentBlock:  int(proxy.CurrentBlock),
+		HighestBlock:  int(proxy.HighestBlock),
+	}
 
 	return nil
 }
@@ -41,6 +45,27 @@ type Transaction struct {
 	Gas              int
 	GasPrice         big.Int
 	Input            string
+
+	// Type is 0 for legacy, 1 for EIP-2930 access list and 2 for EIP-1559 dynamic fee transaction
+	Type int
+	// ChainID is nil for legacy transaction without EIP-155 replay protection
+	ChainID *big.Int
+	// MaxFeePerGas and MaxPriorityFeePerGas are nil before EIP-1559 transaction type
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	AccessList           []AccessTuple
+	V                    big.Int
+	R                    big.Int
+	S                    big.Int
+	// MaxFeePerBlobGas and BlobVersionedHashes are set for EIP-4844 blob transaction, type 3
+	MaxFeePerBlobGas    *big.Int
+	BlobVersionedHashes []string
+}
+
+// AccessTuple - address and storage keys accessed by transaction, EIP-2930
+type AccessTuple struct {
+	Address     string   `json:"address"`
+	StorageKeys []string `json:"storageKeys"`
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
@@ -50,7 +75,7 @@ func (t *Transaction) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	*t = *(*Transaction)(unsafe.Pointer(proxy))
+	*t = proxy.toTransaction()
 
 	return nil
 }
@@ -75,11 +100,47 @@ func (log *Log) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	*log = *(*Log)(unsafe.Pointer(proxy))
+	*log = Log{
+		Removed:          proxy.Removed,
+		LogIndex:         int(proxy.LogIndex),
+		TransactionIndex: int(proxy.TransactionIndex),
+		TransactionHash:  proxy.TransactionHash,
+		BlockNumber:      int(proxy.BlockNumber),
+		BlockHash:        proxy.BlockHash,
+		Address:          proxy.Address,
+		Data:             proxy.Data,
+		Topics:           proxy.Topics,
+	}
 
 	return nil
 }
 
+// BlockNumberOrTag - block parameter of block scoped methods, hex block number or one of block tags
+type BlockNumberOrTag string
+
+// block tags
+const (
+	BlockEarliest  BlockNumberOrTag = "earliest"
+	BlockLatest    BlockNumberOrTag = "latest"
+	BlockPending   BlockNumberOrTag = "pending"
+	BlockSafe      BlockNumberOrTag = "safe"
+	BlockFinalized BlockNumberOrTag = "finalized"
+)
+
+// BlockNumber returns block parameter of block number
+func BlockNumber(number int) BlockNumberOrTag {
+	return BlockNumberOrTag(IntToHex(number))
+}
+
+// Number returns block number of block parameter, ok is false for block tags
+func (block BlockNumberOrTag) Number() (number int, ok bool) {
+	if !strings.HasPrefix(string(block), "0x") {
+		return 0, false
+	}
+	number, err := ParseInt(string(block))
+	return number, err == nil
+}
+
 // FilterParams - Filter parameters object
 type FilterParams struct {
 	FromBlock string     `json:"fromBlock,omitempty"`
@@ -88,6 +149,66 @@ type FilterParams struct {
 	Topics    [][]string `json:"topics,omitempty"`
 }
 
+// CallMsg - call message of eth_call, zero fields are omitted
+type CallMsg struct {
+	From     string
+	To       string
+	Gas      int
+	GasPrice *big.Int
+	Value    *big.Int
+	Data     string
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (msg CallMsg) MarshalJSON() ([]byte, error) {
+	proxy := proxyCallMsg{
+		From: msg.From,
+		To:   msg.To,
+		Data: msg.Data,
+	}
+	if msg.Gas != 0 {
+		proxy.Gas = IntToHex(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		proxy.GasPrice = BigToHex(*msg.GasPrice)
+	}
+	if msg.Value != nil {
+		proxy.Value = BigToHex(*msg.Value)
+	}
+
+	return json.Marshal(proxy)
+}
+
+// StateOverride - account state replaced for the duration of eth_call, by address
+type StateOverride map[string]OverrideAccount
+
+// OverrideAccount - account fields to override, State replaces the whole storage
+// while StateDiff replaces given slots only
+type OverrideAccount struct {
+	Nonce     *int
+	Code      string
+	Balance   *big.Int
+	State     map[string]string
+	StateDiff map[string]string
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (account OverrideAccount) MarshalJSON() ([]byte, error) {
+	proxy := proxyOverrideAccount{
+		Code:      account.Code,
+		State:     account.State,
+		StateDiff: account.StateDiff,
+	}
+	if account.Nonce != nil {
+		proxy.Nonce = IntToHex(*account.Nonce)
+	}
+	if account.Balance != nil {
+		proxy.Balance = BigToHex(*account.Balance)
+	}
+
+	return json.Marshal(proxy)
+}
+
 // TransactionReceipt - transaction receipt object
 type TransactionReceipt struct {
 	TransactionHash   string
@@ -101,6 +222,13 @@ type TransactionReceipt struct {
 	LogsBloom         string
 	Root              string
 	Status            int
+	EffectiveGasPrice big.Int
+	Type              int
+	From              string
+	To                string
+	// BlobGasUsed and BlobGasPrice are set for blob transaction
+	BlobGasUsed  int
+	BlobGasPrice *big.Int
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
@@ -110,7 +238,25 @@ func (t *TransactionReceipt) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	*t = *(*TransactionReceipt)(unsafe.Pointer(proxy))
+	*t = TransactionReceipt{
+		TransactionHash:   proxy.TransactionHash,
+		TransactionIndex:  int(proxy.TransactionIndex),
+		BlockHash:         proxy.BlockHash,
+		BlockNumber:       int(proxy.BlockNumber),
+		CumulativeGasUsed: int(proxy.CumulativeGasUsed),
+		GasUsed:           int(proxy.GasUsed),
+		ContractAddress:   proxy.ContractAddress,
+		Logs:              proxy.Logs,
+		LogsBloom:         proxy.LogsBloom,
+		Root:              proxy.Root,
+		Status:            int(proxy.Status),
+		EffectiveGasPrice: big.Int(proxy.EffectiveGasPrice),
+		Type:              int(proxy.Type),
+		From:              proxy.From,
+		To:                proxy.To,
+		BlobGasUsed:       int(proxy.BlobGasUsed),
+		BlobGasPrice:      (*big.Int)(proxy.BlobGasPrice),
+	}
 
 	return nil
 }
@@ -135,6 +281,26 @@ type Block struct {
 	Timestamp        int
 	Uncles           []string
 	Transactions     []Transaction
+	// BaseFeePerGas is nil before London
+	BaseFeePerGas *big.Int
+	MixHash       string
+	ReceiptsRoot  string
+	// Withdrawals and WithdrawalsRoot are set since Shanghai
+	Withdrawals     []Withdrawal
+	WithdrawalsRoot string
+	// BlobGasUsed, ExcessBlobGas and ParentBeaconBlockRoot are set since Cancun
+	BlobGasUsed           *int
+	ExcessBlobGas         *int
+	ParentBeaconBlockRoot string
+}
+
+// Withdrawal - validator withdrawal from beacon chain, EIP-4895
+type Withdrawal struct {
+	Index          int
+	ValidatorIndex int
+	Address        string
+	// Amount is in gwei
+	Amount int
 }
 
 // {"difficulty":"0xcb5d1dadda318",
@@ -195,7 +361,6 @@ func (proxy *UncleBlock) ToBlock() Block {
 }
 
 type proxySyncing struct {
-	IsSyncing     bool   `json:"-"`
 	StartingBlock hexInt `json:"startingBlock"`
 	CurrentBlock  hexInt `json:"currentBlock"`
 	HighestBlock  hexInt `json:"highestBlock"`
@@ -213,6 +378,43 @@ type proxyTransaction struct {
 	Gas              hexInt  `json:"gas"`
 	GasPrice         hexBig  `json:"gasPrice"`
 	Input            string  `json:"input"`
+
+	Type                 hexInt        `json:"type"`
+	ChainID              *hexBig       `json:"chainId"`
+	MaxFeePerGas         *hexBig       `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexBig       `json:"maxPriorityFeePerGas"`
+	AccessList           []AccessTuple `json:"accessList"`
+	V                    hexBig        `json:"v"`
+	R                    hexBig        `json:"r"`
+	S                    hexBig        `json:"s"`
+	MaxFeePerBlobGas     *hexBig       `json:"maxFeePerBlobGas"`
+	BlobVersionedHashes  []string      `json:"blobVersionedHashes"`
+}
+
+func (proxy *proxyTransaction) toTransaction() Transaction {
+	return Transaction{
+		Hash:                 proxy.Hash,
+		Nonce:                int(proxy.Nonce),
+		BlockHash:            proxy.BlockHash,
+		BlockNumber:          (*int)(proxy.BlockNumber),
+		TransactionIndex:     (*int)(proxy.TransactionIndex),
+		From:                 proxy.From,
+		To:                   proxy.To,
+		Value:                big.Int(proxy.Value),
+		Gas:                  int(proxy.Gas),
+		GasPrice:             big.Int(proxy.GasPrice),
+		Input:                proxy.Input,
+		Type:                 int(proxy.Type),
+		ChainID:              (*big.Int)(proxy.ChainID),
+		MaxFeePerGas:         (*big.Int)(proxy.MaxFeePerGas),
+		MaxPriorityFeePerGas: (*big.Int)(proxy.MaxPriorityFeePerGas),
+		AccessList:           proxy.AccessList,
+		V:                    big.Int(proxy.V),
+		R:                    big.Int(proxy.R),
+		S:                    big.Int(proxy.S),
+		MaxFeePerBlobGas:     (*big.Int)(proxy.MaxFeePerBlobGas),
+		BlobVersionedHashes:  proxy.BlobVersionedHashes,
+	}
 }
 
 type proxyLog struct {
@@ -227,24 +429,60 @@ type proxyLog struct {
 	Topics           []string `json:"topics"`
 }
 
+type proxyCallMsg struct {
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+	Gas      string `json:"gas,omitempty"`
+	GasPrice string `json:"gasPrice,omitempty"`
+	Value    string `json:"value,omitempty"`
+	Data     string `json:"data,omitempty"`
+}
+
+type proxyOverrideAccount struct {
+	Nonce     string            `json:"nonce,omitempty"`
+	Code      string            `json:"code,omitempty"`
+	Balance   string            `json:"balance,omitempty"`
+	State     map[string]string `json:"state,omitempty"`
+	StateDiff map[string]string `json:"stateDiff,omitempty"`
+}
+
 type proxyTransactionReceipt struct {
-	TransactionHash   string `json:"transactionHash"`
-	TransactionIndex  hexInt `json:"transactionIndex"`
-	BlockHash         string `json:"blockHash"`
-	BlockNumber       hexInt `json:"blockNumber"`
-	CumulativeGasUsed hexInt `json:"cumulativeGasUsed"`
-	GasUsed           hexInt `json:"gasUsed"`
-	ContractAddress   string `json:"contractAddress,omitempty"`
-	Logs              []Log  `json:"logs"`
-	LogsBloom         string `json:"logsBloom"`
-	Root              string `json:"root"`
-	Status            hexInt `json:"status"`
+	TransactionHash   string  `json:"transactionHash"`
+	TransactionIndex  hexInt  `json:"transactionIndex"`
+	BlockHash         string  `json:"blockHash"`
+	BlockNumber       hexInt  `json:"blockNumber"`
+	CumulativeGasUsed hexInt  `json:"cumulativeGasUsed"`
+	GasUsed           hexInt  `json:"gasUsed"`
+	ContractAddress   string  `json:"contractAddress,omitempty"`
+	Logs              []Log   `json:"logs"`
+	LogsBloom         string  `json:"logsBloom"`
+	Root              string  `json:"root"`
+	Status            hexInt  `json:"status"`
+	EffectiveGasPrice hexBig  `json:"effectiveGasPrice"`
+	Type              hexInt  `json:"type"`
+	From              string  `json:"from"`
+	To                string  `json:"to"`
+	BlobGasUsed       hexInt  `json:"blobGasUsed"`
+	BlobGasPrice      *hexBig `json:"blobGasPrice"`
+}
+
+type proxyWithdrawal struct {
+	Index          hexInt `json:"index"`
+	ValidatorIndex hexInt `json:"validatorIndex"`
+	Address        string `json:"address"`
+	Amount         hexInt `json:"amount"`
 }
 
 type hexInt int
 
 func (i *hexInt) UnmarshalJSON(data []byte) error {
-	result, err := ParseInt(string(bytes.Trim(data, `"`)))
+	value := string(bytes.Trim(data, `"`))
+	// etherscan encodes zero log and transaction index as "0x"
+	if value == "0x" {
+		*i = 0
+		return nil
+	}
+	result, err := ParseInt(value)
 	*i = hexInt(result)
 
 	return err
@@ -259,29 +497,92 @@ func (i *hexBig) UnmarshalJSON(data []byte) error {
 	return err
 }
 
+// proxyBlockHeader - fields of block object but transactions
+type proxyBlockHeader struct {
+	Number                hexInt            `json:"number"`
+	Hash                  string            `json:"hash"`
+	ParentHash            string            `json:"parentHash"`
+	Nonce                 string            `json:"nonce"`
+	Sha3Uncles            string            `json:"sha3Uncles"`
+	LogsBloom             string            `json:"logsBloom"`
+	TransactionsRoot      string            `json:"transactionsRoot"`
+	StateRoot             string            `json:"stateRoot"`
+	Miner                 string            `json:"miner"`
+	Difficulty            hexBig            `json:"difficulty"`
+	TotalDifficulty       hexBig            `json:"totalDifficulty"`
+	ExtraData             string            `json:"extraData"`
+	Size                  hexInt            `json:"size"`
+	GasLimit              hexInt            `json:"gasLimit"`
+	GasUsed               hexInt            `json:"gasUsed"`
+	Timestamp             hexInt            `json:"timestamp"`
+	Uncles                []string          `json:"uncles"`
+	BaseFeePerGas         *hexBig           `json:"baseFeePerGas"`
+	MixHash               string            `json:"mixHash"`
+	ReceiptsRoot          string            `json:"receiptsRoot"`
+	Withdrawals           []proxyWithdrawal `json:"withdrawals"`
+	WithdrawalsRoot       string            `json:"withdrawalsRoot"`
+	BlobGasUsed           *hexInt           `json:"blobGasUsed"`
+	ExcessBlobGas         *hexInt           `json:"excessBlobGas"`
+	ParentBeaconBlockRoot string            `json:"parentBeaconBlockRoot"`
+}
+
+func (proxy *proxyBlockHeader) toBlock() Block {
+	block := Block{
+		Number:                int(proxy.Number),
+		Hash:                  proxy.Hash,
+		ParentHash:            proxy.ParentHash,
+		Nonce:                 proxy.Nonce,
+		Sha3Uncles:            proxy.Sha3Uncles,
+		LogsBloom:             proxy.LogsBloom,
+		TransactionsRoot:      proxy.TransactionsRoot,
+		StateRoot:             proxy.StateRoot,
+		Miner:                 proxy.Miner,
+		Difficulty:            big.Int(proxy.Difficulty),
+		TotalDifficulty:       big.Int(proxy.TotalDifficulty),
+		ExtraData:             proxy.ExtraData,
+		Size:                  int(proxy.Size),
+		GasLimit:              int(proxy.GasLimit),
+		GasUsed:               int(proxy.GasUsed),
+		Timestamp:             int(proxy.Timestamp),
+		Uncles:                proxy.Uncles,
+		BaseFeePerGas:         (*big.Int)(proxy.BaseFeePerGas),
+		MixHash:               proxy.MixHash,
+		ReceiptsRoot:          proxy.ReceiptsRoot,
+		WithdrawalsRoot:       proxy.WithdrawalsRoot,
+		BlobGasUsed:           (*int)(proxy.BlobGasUsed),
+		ExcessBlobGas:         (*int)(proxy.ExcessBlobGas),
+		ParentBeaconBlockRoot: proxy.ParentBeaconBlockRoot,
+	}
+
+	if proxy.Withdrawals != nil {
+		block.Withdrawals = make([]Withdrawal, len(proxy.Withdrawals))
+		for i, withdrawal := range proxy.Withdrawals {
+			block.Withdrawals[i] = Withdrawal{
+				Index:          int(withdrawal.Index),
+				ValidatorIndex: int(withdrawal.ValidatorIndex),
+				Address:        withdrawal.Address,
+				Amount:         int(withdrawal.Amount),
+			}
+		}
+	}
+
+	return block
+}
+
 type ProxyBlockWithTransactions struct {
-	Number           hexInt             `json:"number"`
-	Hash             string             `json:"hash"`
-	ParentHash       string             `json:"parentHash"`
-	Nonce            string             `json:"nonce"`
-	Sha3Uncles       string             `json:"sha3Uncles"`
-	LogsBloom        string             `json:"logsBloom"`
-	TransactionsRoot string             `json:"transactionsRoot"`
-	StateRoot        string             `json:"stateRoot"`
-	Miner            string             `json:"miner"`
-	Difficulty       hexBig             `json:"difficulty"`
-	TotalDifficulty  hexBig             `json:"totalDifficulty"`
-	ExtraData        string             `json:"extraData"`
-	Size             hexInt             `json:"size"`
-	GasLimit         hexInt             `json:"gasLimit"`
-	GasUsed          hexInt             `json:"gasUsed"`
-	Timestamp        hexInt             `json:"timestamp"`
-	Uncles           []string           `json:"uncles"`
-	Transactions     []proxyTransaction `json:"transactions"`
+	proxyBlockHeader
+	Transactions []proxyTransaction `json:"transactions"`
 }
 
 func (proxy *ProxyBlockWithTransactions) ToBlock() Block {
-	return *(*Block)(unsafe.Pointer(proxy))
+	block := proxy.toBlock()
+
+	block.Transactions = make([]Transaction, len(proxy.Transactions))
+	for i := range proxy.Transactions {
+		block.Transactions[i] = proxy.Transactions[i].toTransaction()
+	}
+
+	return block
 }
 
 type ProxyBlock interface {
@@ -289,46 +590,12 @@ type ProxyBlock interface {
 }
 
 type ProxyBlockWithoutTransactions struct {
-	Number           hexInt   `json:"number"`
-	Hash             string   `json:"hash"`
-	ParentHash       string   `json:"parentHash"`
-	Nonce            string   `json:"nonce"`
-	Sha3Uncles       string   `json:"sha3Uncles"`
-	LogsBloom        string   `json:"logsBloom"`
-	TransactionsRoot string   `json:"transactionsRoot"`
-	StateRoot        string   `json:"stateRoot"`
-	Miner            string   `json:"miner"`
-	Difficulty       hexBig   `json:"difficulty"`
-	TotalDifficulty  hexBig   `json:"totalDifficulty"`
-	ExtraData        string   `json:"extraData"`
-	Size             hexInt   `json:"size"`
-	GasLimit         hexInt   `json:"gasLimit"`
-	GasUsed          hexInt   `json:"gasUsed"`
-	Timestamp        hexInt   `json:"timestamp"`
-	Uncles           []string `json:"uncles"`
-	Transactions     []string `json:"transactions"`
+	proxyBlockHeader
+	Transactions []string `json:"transactions"`
 }
 
 func (proxy *ProxyBlockWithoutTransactions) ToBlock() Block {
-	block := Block{
-		Number:           int(proxy.Number),
-		Hash:             proxy.Hash,
-		ParentHash:       proxy.ParentHash,
-		Nonce:            proxy.Nonce,
-		Sha3Uncles:       proxy.Sha3Uncles,
-		LogsBloom:        proxy.LogsBloom,
-		TransactionsRoot: proxy.TransactionsRoot,
-		StateRoot:        proxy.StateRoot,
-		Miner:            proxy.Miner,
-		Difficulty:       big.Int(proxy.Difficulty),
-		TotalDifficulty:  big.Int(proxy.TotalDifficulty),
-		ExtraData:        proxy.ExtraData,
-		Size:             int(proxy.Size),
-		GasLimit:         int(proxy.GasLimit),
-		GasUsed:          int(proxy.GasUsed),
-		Timestamp:        int(proxy.Timestamp),
-		Uncles:           proxy.Uncles,
-	}
+	block := proxy.toBlock()
 
 	block.Transactions = make([]Transaction, len(proxy.Transactions))
 	for i := range proxy.Transactions {
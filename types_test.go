@@ -2,6 +2,7 @@ package ethrpc
 
 import (
 	"encoding/json"
+	"fmt"
 	"math/big"
 	"testing"
 
@@ -172,3 +173,233 @@ func TestTransactionReceiptUnmarshal(t *testing.T) {
 	require.Equal(t, 6, receipt.Logs[0].LogIndex)
 	require.Equal(t, false, receipt.Logs[0].Removed)
 }
+
+func TestBlockNumberOrTag(t *testing.T) {
+	require.Equal(t, BlockNumberOrTag("0x5dd091"), BlockNumber(6148241))
+
+	number, ok := BlockNumber(6148241).Number()
+	require.True(t, ok)
+	require.Equal(t, 6148241, number)
+
+	_, ok = BlockFinalized.Number()
+	require.False(t, ok)
+
+	data, err := json.Marshal([]interface{}{BlockSafe, BlockNumber(16)})
+	require.Nil(t, err)
+	require.Equal(t, `["safe","0x10"]`, string(data))
+}
+
+func TestDynamicFeeTransactionUnmarshal(t *testing.T) {
+	data := []byte(`{
+        "accessList": [{
+            "address": "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+            "storageKeys": ["0x0000000000000000000000000000000000000000000000000000000000000003"]
+        }],
+        "blockHash": "0x6c5a1f8a1d3f4dc3a3e2a1cf3cbd2df1bdf18a2d4b9e3a9c8b7a6f5e4d3c2b1a",
+        "blockNumber": "0x1039a3f",
+        "chainId": "0x1",
+        "from": "0xae2fc483527b8ef99eb5d9b44875f005ba1fae13",
+        "gas": "0x3d090",
+        "gasPrice": "0x4a817c800",
+        "hash": "0x9d8e1e7f2c3b4a5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5",
+        "input": "0x",
+        "maxFeePerGas": "0x6fc23ac00",
+        "maxPriorityFeePerGas": "0x3b9aca00",
+        "nonce": "0x2a",
+        "r": "0x8a1c5d0e2f3b4a6978c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e1f2a3",
+        "s": "0x1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e",
+        "to": "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+        "transactionIndex": "0x0",
+        "type": "0x2",
+        "v": "0x1",
+        "value": "0xde0b6b3a7640000",
+        "yParity": "0x1"
+    }`)
+
+	tx := new(Transaction)
+	require.Nil(t, json.Unmarshal(data, tx))
+	require.Equal(t, 2, tx.Type)
+	require.Equal(t, "1", tx.ChainID.String())
+	require.Equal(t, "30000000000", tx.MaxFeePerGas.String())
+	require.Equal(t, "1000000000", tx.MaxPriorityFeePerGas.String())
+	require.Equal(t, []AccessTuple{{
+		Address:     "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+		StorageKeys: []string{"0x0000000000000000000000000000000000000000000000000000000000000003"},
+	}}, tx.AccessList)
+	require.Equal(t, "1", tx.V.String())
+	require.Equal(t, "0x8a1c5d0e2f3b4a6978c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e1f2a3", BigToHex(tx.R))
+	require.Equal(t, "0x1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e", BigToHex(tx.S))
+	require.Equal(t, "1000000000000000000", tx.Value.String())
+	require.Equal(t, 17013311, *tx.BlockNumber)
+
+	// legacy transaction has no dynamic fee fields
+	legacy := new(Transaction)
+	require.Nil(t, json.Unmarshal([]byte(`{"hash":"0x1","gasPrice":"0x1","value":"0x0","gas":"0x5208","nonce":"0x0","v":"0x1b","r":"0x1","s":"0x2"}`), legacy))
+	require.Equal(t, 0, legacy.Type)
+	require.Nil(t, legacy.ChainID)
+	require.Nil(t, legacy.MaxFeePerGas)
+	require.Equal(t, "27", legacy.V.String())
+}
+
+const shanghaiBlock = `{
+    "baseFeePerGas": "0x5e0a2b3c1",
+    "difficulty": "0x0",
+    "extraData": "0x6265617665726275696c642e6f7267",
+    "gasLimit": "0x1c9c380",
+    "gasUsed": "0xb6e1a3",
+    "hash": "0x5a4bd0a7f1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c",
+    "logsBloom": "0x00",
+    "miner": "0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5",
+    "mixHash": "0x3a8b5c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b",
+    "nonce": "0x0000000000000000",
+    "number": "0x1036c6f",
+    "parentHash": "0x1f0e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c4b5a69788796a5b4c3d2e1f0",
+    "receiptsRoot": "0x7e6d5c4b3a29181706f5e4d3c2b1a0f9e8d7c6b5a4938271605f4e3d2c1b0a99",
+    "sha3Uncles": "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347",
+    "size": "0x1a3f2",
+    "stateRoot": "0x2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f",
+    "timestamp": "0x643a8b5f",
+    "totalDifficulty": "0xc70d815d562d3cfa955",
+    "transactions": [%s],
+    "transactionsRoot": "0x4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a",
+    "uncles": [],
+    "withdrawals": [{
+        "address": "0xb9d7934878b5fb9610b3fe8a5e441e8fad7e293f",
+        "amount": "0xdbc4a4",
+        "index": "0x3e8",
+        "validatorIndex": "0x9d3a"
+    }],
+    "withdrawalsRoot": "0x9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b"
+}`
+
+func TestShanghaiBlockUnmarshal(t *testing.T) {
+	requireShanghai := func(block *Block) {
+		require.Equal(t, 17001583, block.Number)
+		require.Equal(t, "25243595713", block.BaseFeePerGas.String())
+		require.Equal(t, "0x3a8b5c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b", block.MixHash)
+		require.Equal(t, "0x7e6d5c4b3a29181706f5e4d3c2b1a0f9e8d7c6b5a4938271605f4e3d2c1b0a99", block.ReceiptsRoot)
+		require.Equal(t, "0x9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b", block.WithdrawalsRoot)
+		require.Equal(t, []Withdrawal{{
+			Index:          1000,
+			ValidatorIndex: 40250,
+			Address:        "0xb9d7934878b5fb9610b3fe8a5e441e8fad7e293f",
+			Amount:         14402724,
+		}}, block.Withdrawals)
+	}
+
+	block, err := decodeBlock(json.RawMessage(fmt.Sprintf(shanghaiBlock, `"0x9d8e1e7f2c3b4a5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5"`)), false)
+	require.Nil(t, err)
+	requireShanghai(block)
+	require.Equal(t, "0x9d8e1e7f2c3b4a5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5", block.Transactions[0].Hash)
+
+	block, err = decodeBlock(json.RawMessage(fmt.Sprintf(shanghaiBlock, `{"hash":"0x9d8e1e7f2c3b4a5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5","type":"0x2","gasPrice":"0x5e0a2b3c1","maxFeePerGas":"0x6fc23ac00","maxPriorityFeePerGas":"0x0","value":"0x0","gas":"0x5208","nonce":"0x1","v":"0x0","r":"0x1","s":"0x1"}`)), true)
+	require.Nil(t, err)
+	requireShanghai(block)
+	require.Equal(t, 2, block.Transactions[0].Type)
+	require.Equal(t, "0", block.Transactions[0].MaxPriorityFeePerGas.String())
+
+	// pre London block
+	block, err = decodeBlock(json.RawMessage(`{"number":"0x5dd091","hash":"0xef7fa50f455e5c40f3435f2e1ede71fabf670f265ad623fb804ae2eb3c1d0db3","difficulty":"0xcb5d1dadda318","transactions":[]}`), false)
+	require.Nil(t, err)
+	require.Nil(t, block.BaseFeePerGas)
+	require.Nil(t, block.Withdrawals)
+}
+
+func TestDynamicFeeReceiptUnmarshal(t *testing.T) {
+	data := []byte(`{
+        "blockHash": "0x6c5a1f8a1d3f4dc3a3e2a1cf3cbd2df1bdf18a2d4b9e3a9c8b7a6f5e4d3c2b1a",
+        "blockNumber": "0x1039a3f",
+        "contractAddress": null,
+        "cumulativeGasUsed": "0x5208",
+        "effectiveGasPrice": "0x5e0a2b3c1",
+        "from": "0xae2fc483527b8ef99eb5d9b44875f005ba1fae13",
+        "gasUsed": "0x5208",
+        "logs": [],
+        "logsBloom": "0x00",
+        "status": "0x1",
+        "to": "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+        "transactionHash": "0x9d8e1e7f2c3b4a5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5",
+        "transactionIndex": "0x0",
+        "type": "0x2"
+    }`)
+
+	receipt := new(TransactionReceipt)
+	require.Nil(t, json.Unmarshal(data, receipt))
+	require.Equal(t, "25243595713", receipt.EffectiveGasPrice.String())
+	require.Equal(t, 2, receipt.Type)
+	require.Equal(t, "0xae2fc483527b8ef99eb5d9b44875f005ba1fae13", receipt.From)
+	require.Equal(t, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", receipt.To)
+	require.Equal(t, 1, receipt.Status)
+	require.Equal(t, 21000, receipt.GasUsed)
+}
+
+func TestCancunUnmarshal(t *testing.T) {
+	data := json.RawMessage(`{
+        "baseFeePerGas": "0x3b9aca00",
+        "blobGasUsed": "0x40000",
+        "difficulty": "0x0",
+        "excessBlobGas": "0x4b0000",
+        "gasLimit": "0x1c9c380",
+        "gasUsed": "0x5208",
+        "hash": "0x0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f9",
+        "number": "0x12a05f2",
+        "parentBeaconBlockRoot": "0x8f7e6d5c4b3a29180f7e6d5c4b3a29180f7e6d5c4b3a29180f7e6d5c4b3a2918",
+        "timestamp": "0x65f1b057",
+        "transactions": [{
+            "blobVersionedHashes": ["0x01a915e4d060149eb4365960e6a7a45f334393093061116b197e3240065ff2d8"],
+            "chainId": "0x1",
+            "gas": "0x5208",
+            "gasPrice": "0x3b9aca00",
+            "hash": "0x5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f",
+            "input": "0x",
+            "maxFeePerBlobGas": "0x3b9aca00",
+            "maxFeePerGas": "0x77359400",
+            "maxPriorityFeePerGas": "0x3b9aca00",
+            "nonce": "0x7",
+            "r": "0x1",
+            "s": "0x2",
+            "to": "0xff00000000000000000000000000000000000001",
+            "type": "0x3",
+            "v": "0x0",
+            "value": "0x0"
+        }],
+        "uncles": [],
+        "withdrawals": [],
+        "withdrawalsRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"
+    }`)
+
+	block, err := decodeBlock(data, true)
+	require.Nil(t, err)
+	require.Equal(t, 262144, *block.BlobGasUsed)
+	require.Equal(t, 4915200, *block.ExcessBlobGas)
+	require.Equal(t, "0x8f7e6d5c4b3a29180f7e6d5c4b3a29180f7e6d5c4b3a29180f7e6d5c4b3a2918", block.ParentBeaconBlockRoot)
+	require.Equal(t, []Withdrawal{}, block.Withdrawals)
+	require.Equal(t, "4", BlobBaseFee(*block.ExcessBlobGas, BlobBaseFeeUpdateFractionCancun).String())
+
+	tx := block.Transactions[0]
+	require.Equal(t, 3, tx.Type)
+	require.Equal(t, "1000000000", tx.MaxFeePerBlobGas.String())
+	require.Equal(t, []string{"0x01a915e4d060149eb4365960e6a7a45f334393093061116b197e3240065ff2d8"}, tx.BlobVersionedHashes)
+	require.Equal(t, "2000000000", tx.MaxFeePerGas.String())
+	require.Equal(t, 7, tx.Nonce)
+
+	receipt := new(TransactionReceipt)
+	require.Nil(t, json.Unmarshal([]byte(`{
+        "blobGasPrice": "0x5",
+        "blobGasUsed": "0x20000",
+        "blockNumber": "0x12a05f2",
+        "effectiveGasPrice": "0x3b9aca00",
+        "gasUsed": "0x5208",
+        "status": "0x1",
+        "transactionHash": "0x5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f",
+        "type": "0x3"
+    }`), receipt))
+	require.Equal(t, 131072, receipt.BlobGasUsed)
+	require.Equal(t, "5", receipt.BlobGasPrice.String())
+
+	// block before Cancun has no blob fields
+	block, err = decodeBlock(json.RawMessage(`{"number":"0x1","hash":"0x1","difficulty":"0x1","transactions":[]}`), false)
+	require.Nil(t, err)
+	require.Nil(t, block.BlobGasUsed)
+	require.Nil(t, block.ExcessBlobGas)
+}
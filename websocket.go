@@ -0,0 +1,79 @@
+package ethrpc
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocketTransport sends json-rpc requests over websocket and supports subscriptions
+type WebSocketTransport struct {
+	streamTransport
+
+	url    string
+	dialer *websocket.Dialer
+	header http.Header
+}
+
+// WebSocketTransportDialer set dialer used to open connection
+func WebSocketTransportDialer(dialer *websocket.Dialer) func(x *WebSocketTransport) {
+	return func(x *WebSocketTransport) {
+		x.dialer = dialer
+	}
+}
+
+// WebSocketTransportAuth set auth policy applied to the websocket handshake
+func WebSocketTransportAuth(auth HTTPAuth) func(x *WebSocketTransport) {
+	return func(x *WebSocketTransport) {
+		req := &http.Request{Header: x.header}
+		auth(req)
+	}
+}
+
+// NewWebSocketTransport create new websocket transport with given ws:// or wss:// url,
+// connection is opened on first request
+func NewWebSocketTransport(url string, options ...func(x *WebSocketTransport)) *WebSocketTransport {
+	transport := &WebSocketTransport{
+		url:    url,
+		dialer: websocket.DefaultDialer,
+		header: http.Header{},
+	}
+	transport.streamTransport = newStreamTransport("ws-"+url, transport.dialContext)
+	for _, option := range options {
+		option(transport)
+	}
+
+	return transport
+}
+
+func (x *WebSocketTransport) dialContext(ctx context.Context) (streamConn, error) {
+	conn, _, err := x.dialer.DialContext(ctx, x.url, x.header)
+	if err != nil {
+		return nil, err
+	}
+	return &wsConn{conn: conn}, nil
+}
+
+type wsConn struct {
+	conn *websocket.Conn
+}
+
+func (c *wsConn) Write(ctx context.Context, data []byte) error {
+	deadline, _ := ctx.Deadline()
+	if err := c.conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	return c.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (c *wsConn) Read() ([]byte, error) {
+	_, data, err := c.conn.ReadMessage()
+	return data, err
+}
+
+func (c *wsConn) Close() error {
+	c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return c.conn.Close()
+}
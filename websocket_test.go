@@ -0,0 +1,128 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWSNode answers eth_blockNumber and eth_subscribe newHeads,
+// every subscription receives one header then the connection is dropped
+type fakeWSNode struct {
+	mu         sync.Mutex
+	subscribes int
+}
+
+func (n *fakeWSNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		if data[0] == '[' {
+			var reqs []EthRequest
+			json.Unmarshal(data, &reqs)
+			resps := make([]EthResponse, len(reqs))
+			for i := len(reqs) - 1; i >= 0; i-- {
+				resps[len(reqs)-1-i] = EthResponse{ID: reqs[i].ID, Version: "2.0", Result: json.RawMessage(`"0x10"`)}
+			}
+			conn.WriteJSON(resps)
+			continue
+		}
+
+		var req EthRequest
+		json.Unmarshal(data, &req)
+		switch req.Method {
+		case "eth_blockNumber":
+			conn.WriteJSON(EthResponse{ID: req.ID, Version: "2.0", Result: json.RawMessage(`"0x10"`)})
+		case "eth_subscribe":
+			n.mu.Lock()
+			n.subscribes++
+			id := n.subscribes
+			n.mu.Unlock()
+
+			conn.WriteJSON(EthResponse{ID: req.ID, Version: "2.0", Result: json.RawMessage(`"0xsub` + IntToHex(id)[2:] + `"`)})
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"eth_subscription","params":{"subscription":"0xsub`+IntToHex(id)[2:]+`","result":{"number":"`+IntToHex(id)+`","hash":"0xh`+IntToHex(id)[2:]+`"}}}`))
+			return
+		default:
+			conn.WriteJSON(EthResponse{ID: req.ID, Version: "2.0", Error: &EthError{Code: -32601, Message: "method not found"}})
+		}
+	}
+}
+
+func newFakeWSNode() (*fakeWSNode, *httptest.Server) {
+	node := new(fakeWSNode)
+	server := httptest.NewServer(node)
+	return node, server
+}
+
+func TestWebSocketTransportCall(t *testing.T) {
+	_, server := newFakeWSNode()
+	defer server.Close()
+
+	rpc := NewNodeAPI(strings.Replace(server.URL, "http://", "ws://", 1))
+	defer rpc.Close()
+
+	number, err := rpc.EthBlockNumber()
+	require.Nil(t, err)
+	require.Equal(t, 16, number)
+
+	_, err = rpc.NetVersion()
+	require.Equal(t, EthError{Code: -32601, Message: "method not found"}, err)
+
+	resps, err := rpc.BatchCall([]EthRequest{
+		NewEthRequest("eth_blockNumber"),
+		NewEthRequest("eth_blockNumber"),
+	})
+	require.Nil(t, err)
+	require.Len(t, resps, 2)
+	require.Equal(t, `"0x10"`, string(resps[0].Result))
+}
+
+func TestWebSocketTransportResubscribe(t *testing.T) {
+	_, server := newFakeWSNode()
+	defer server.Close()
+
+	transport := NewWebSocketTransport(strings.Replace(server.URL, "http://", "ws://", 1))
+	transport.minReconnectDelay = 10 * time.Millisecond
+	rpc := NewNodeAPI("", NodeTransport(transport))
+	defer rpc.Close()
+
+	heads := make(chan *Block)
+	sub, err := rpc.SubscribeNewHeads(context.Background(), heads)
+	require.Nil(t, err)
+	defer sub.Unsubscribe()
+
+	for i := 1; i <= 3; i++ {
+		select {
+		case head := <-heads:
+			require.Equal(t, i, head.Number)
+			require.Equal(t, "0xh"+IntToHex(i)[2:], head.Hash)
+		case err := <-sub.Err():
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting new head")
+		}
+	}
+}
+
+func TestHTTPTransportSubscribe(t *testing.T) {
+	rpc := NewNodeAPI("http://127.0.0.1:8545")
+	_, err := rpc.SubscribeNewPendingTransactions(context.Background(), make(chan string))
+	require.Equal(t, errSubscriptionNotSupported, err)
+}